@@ -0,0 +1,154 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+)
+
+// AberrationConfig configures the detection of aberrant behaviour, as described by Brutlag in Aberrant Behavior
+// Detection in Time Series for Network Monitoring
+type AberrationConfig struct {
+	// GammaDeviation is the exponential smoothing coefficient for the seasonal deviations, must be between 0 and 1
+	GammaDeviation float64
+	// Delta is the number of deviations either side of the forecast the confidence band extends, must be greater than
+	// 0, usually between 2 and 3
+	Delta float64
+	// Window is the number of most recent observations checked for violations when deciding whether there is a
+	// failure, must be at least 1
+	Window int
+	// Threshold is the number of violations within the window at which there is a failure, must be at least 1 and at
+	// most the window
+	Threshold int
+}
+
+// Aberration is the result of checking a single observation for aberrant behaviour
+type Aberration struct {
+	// Forecast is the prediction made for the observation from the previous step
+	Forecast float64
+	// Lower is the lower bound of the confidence band
+	Lower float64
+	// Upper is the upper bound of the confidence band
+	Upper float64
+	// Violation is true if the observation is outside of the confidence band
+	Violation bool
+	// Failure is true if the number of violations within the window ending at this observation has reached the
+	// threshold
+	Failure bool
+}
+
+// DetectAberrations fits the model to the series in the same way as Fit, tracking a smoothed deviation for each
+// position in the season alongside the forecast, and checks each observation against a confidence band of Delta
+// deviations either side of its forecast. The deviation for a position in the season is smoothed as
+// GammaDeviation*|observation - forecast| + (1-GammaDeviation)*deviation, and the band for an observation uses the
+// deviation from before it was smoothed. The deviations start at 0 and are learnt from the first observation at
+// each position in the season, which is never a violation. Missing (NaN) observations are never violations and do not
+// update the deviations. The first observation is used to initialise the level, so its forecast is the observation. As
+// with Fit, the model's state is left unchanged if an error is returned.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the position in the season given by the model's Phase
+// config - The deviation smoothing coefficient, band width and failure rule
+func (m *Model) DetectAberrations(series []float64, config AberrationConfig) ([]Aberration, error) {
+	// Keep the state from before fitting to restore if fitting fails, so the model is still usable
+	previous := m.copy()
+	result, err := m.detectAberrations(series, config)
+	if err != nil {
+		*m = previous
+		return nil, err
+	}
+	return result, nil
+}
+
+// detectAberrations validates the config, the model and the series, fits the model to it checking each observation
+// for aberrant behaviour and ensures the results and the model's state are finite
+func (m *Model) detectAberrations(series []float64, config AberrationConfig) ([]Aberration, error) {
+	err := validateAberrationConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	series, err = m.prepare(series)
+	if err != nil {
+		return nil, err
+	}
+
+	m.initialise(series)
+	deviations := make([]float64, m.SeasonLength)
+	learnt := make([]bool, m.SeasonLength)
+	result := make([]Aberration, len(series))
+	result[0] = Aberration{
+		Forecast: series[0],
+		Lower:    series[0],
+		Upper:    series[0],
+	}
+	violations := 0
+	for i := 1; i < len(series); i++ {
+		slot := m.seasonIndex(i)
+		forecast := m.oneStepForecast()
+		width := config.Delta * deviations[slot]
+		aberration := Aberration{
+			Forecast: forecast,
+			Lower:    forecast - width,
+			Upper:    forecast + width,
+		}
+		val := series[i]
+		if !missing(val) {
+			aberration.Violation = learnt[slot] && (val < aberration.Lower || val > aberration.Upper)
+			deviations[slot] = config.GammaDeviation*math.Abs(val-forecast) + (1-config.GammaDeviation)*deviations[slot]
+			learnt[slot] = true
+		}
+		m.update(val)
+
+		// Count the violations in the window ending at this observation
+		if aberration.Violation {
+			violations++
+		}
+		if i >= config.Window && result[i-config.Window].Violation {
+			violations--
+		}
+		aberration.Failure = violations >= config.Threshold
+		result[i] = aberration
+	}
+	for _, aberration := range result {
+		err = validateFinite([]float64{aberration.Forecast, aberration.Lower, aberration.Upper})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = m.validateFiniteState(nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateAberrationConfig ensures the configuration for aberrant behaviour detection is valid
+func validateAberrationConfig(config AberrationConfig) error {
+	if !(config.GammaDeviation >= 0.0 && config.GammaDeviation <= 1.0) {
+		return &ParamError{Op: "aberration detection", Name: "gamma deviation", Value: config.GammaDeviation, Range: "between 0 and 1"}
+	}
+	if !(config.Delta > 0.0 && !math.IsInf(config.Delta, 1)) {
+		return &ParamError{Op: "aberration detection", Name: "delta", Value: config.Delta, Range: "greater than 0 and finite"}
+	}
+	if config.Window < 1 {
+		return &ParamError{Op: "aberration detection", Name: "window", Value: config.Window, Range: "at least 1"}
+	}
+	if config.Threshold < 1 || config.Threshold > config.Window {
+		return &ParamError{Op: "aberration detection", Name: "threshold", Value: config.Threshold, Range: fmt.Sprintf("at least 1 and at most the window %d", config.Window)}
+	}
+	return nil
+}
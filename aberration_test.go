@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+var aberrationTestSeries = []float64{10, 20, 30, 20, 11, 21, 29, 20, 10, 19, 31, 21, 10, 20, 30, 20, 11, 45, 50, 40, 10, 20, 30, 20}
+
+func TestModelDetectAberrations(t *testing.T) {
+
+	var tests = []struct {
+		description        string
+		expectedViolations []bool
+		expectedFailures   []bool
+		expectedErr        error
+		model              *holtwinters.Model
+		series             []float64
+		config             holtwinters.AberrationConfig
+	}{
+		{
+			"Fail, gamma deviation too high",
+			nil,
+			nil,
+			&holtwinters.ParamError{Op: "aberration detection", Name: "gamma deviation", Value: 1.500000, Range: "between 0 and 1"},
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 1.5, Delta: 2.5, Window: 3, Threshold: 2},
+		},
+		{
+			"Fail, delta not positive",
+			nil,
+			nil,
+			&holtwinters.ParamError{Op: "aberration detection", Name: "delta", Value: 0.000000, Range: "greater than 0 and finite"},
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 0, Window: 3, Threshold: 2},
+		},
+		{
+			"Fail, window too small",
+			nil,
+			nil,
+			&holtwinters.ParamError{Op: "aberration detection", Name: "window", Value: 0, Range: "at least 1"},
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 0, Threshold: 0},
+		},
+		{
+			"Fail, threshold larger than window",
+			nil,
+			nil,
+			&holtwinters.ParamError{Op: "aberration detection", Name: "threshold", Value: 4, Range: "at least 1 and at most the window 3"},
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 3, Threshold: 4},
+		},
+		{
+			"Fail, invalid model",
+			nil,
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			holtwinters.NewModel(holtwinters.Additive, 4, 1.5, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 3, Threshold: 2},
+		},
+		{
+			"Success, sustained anomaly causes failure",
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, true, true, true, true, false, false, true},
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, false, true, true, true, true, false, false},
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 3, Threshold: 2},
+		},
+		{
+			"Success, single violation with threshold of 1 is a failure",
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, true, true, true, true, false, false, true},
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, true, true, true, true, false, false, true},
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			aberrationTestSeries,
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 1, Threshold: 1},
+		},
+		{
+			"Success, missing observation is not a violation",
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, false, true, true, true, true, false, false},
+			[]bool{false, false, false, false, false, false, false, false, false, false, false, false,
+				false, false, false, false, false, false, false, true, true, true, true, false},
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3),
+			append(append(append([]float64{}, aberrationTestSeries[:17]...), math.NaN()), aberrationTestSeries[18:]...),
+			holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 3, Threshold: 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.model.DetectAberrations(test.series, test.config)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if err != nil {
+				return
+			}
+			violations := make([]bool, len(result))
+			failures := make([]bool, len(result))
+			for i, aberration := range result {
+				violations[i] = aberration.Violation
+				failures[i] = aberration.Failure
+			}
+			if !cmp.Equal(test.expectedViolations, violations) {
+				t.Errorf("violations mismatch (-want +got):\n%s", cmp.Diff(test.expectedViolations, violations))
+			}
+			if !cmp.Equal(test.expectedFailures, failures) {
+				t.Errorf("failures mismatch (-want +got):\n%s", cmp.Diff(test.expectedFailures, failures))
+			}
+		})
+	}
+}
+
+func TestModelDetectAberrationsBands(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+	config := holtwinters.AberrationConfig{GammaDeviation: 0.3, Delta: 2.5, Window: 3, Threshold: 2}
+
+	result, err := holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3).DetectAberrations(aberrationTestSeries, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	components, err := holtwinters.NewModel(holtwinters.Additive, 4, 0.3, 0.1, 0.3).Decompose(aberrationTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Forecasts are the same as the one-step-ahead predictions when fitting, and the bands are built from the smoothed
+	// absolute errors of the previous observation at the same position in the season
+	deviations := make([]float64, 4)
+	for i, aberration := range result {
+		forecast := aberrationTestSeries[i] - components.Residual[i]
+		width := config.Delta * deviations[i%4]
+		expected := holtwinters.Aberration{
+			Forecast:  forecast,
+			Lower:     forecast - width,
+			Upper:     forecast + width,
+			Violation: aberration.Violation,
+			Failure:   aberration.Failure,
+		}
+		if !cmp.Equal(expected, aberration, equateApprox) {
+			t.Errorf("aberration %d mismatch (-want +got):\n%s", i, cmp.Diff(expected, aberration, equateApprox))
+		}
+		if i > 0 {
+			deviations[i%4] = config.GammaDeviation*math.Abs(components.Residual[i]) + (1-config.GammaDeviation)*deviations[i%4]
+		}
+	}
+}
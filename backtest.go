@@ -0,0 +1,161 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jthomperoo/holtwinters/metrics"
+)
+
+// PredictFunc smooths a series and appends predictions to it, PredictAdditive and PredictMultiplicative are both
+// PredictFuncs
+type PredictFunc func(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error)
+
+// BacktestConfig configures a rolling-origin backtest
+type BacktestConfig struct {
+	// Predict is the prediction function to backtest, such as PredictAdditive or PredictMultiplicative
+	Predict PredictFunc
+	// SeasonLength is the length of the data's seasons, or AutoSeasonLength to detect it from the initial window
+	SeasonLength int
+	// Alpha is the exponential smoothing coefficient for level
+	Alpha float64
+	// Beta is the exponential smoothing coefficient for trend
+	Beta float64
+	// Gamma is the exponential smoothing coefficient for seasonality
+	Gamma float64
+	// InitialWindow is the number of observations before the first forecast origin, must be at least a full season
+	InitialWindow int
+	// Step is the number of observations the forecast origin moves forward by each time, must be at least 1
+	Step int
+	// Horizon is the number of steps forecast from each origin, must be at least 1
+	Horizon int
+	// Workers is the number of origins to forecast from concurrently, if less than 1 runtime.GOMAXPROCS(0) is used
+	Workers int
+}
+
+// BacktestResult is the result of a rolling-origin backtest
+type BacktestResult struct {
+	// SeasonLength is the season length used, detected from the initial window if AutoSeasonLength was configured
+	SeasonLength int
+	// Origins are the forecast origins, the number of observations each forecast was made from
+	Origins []int
+	// Errors are the forecast errors, forecast minus actual, for each origin and then each step of the horizon, NaN
+	// where the actual value is missing
+	Errors [][]float64
+	// Horizons are the accuracy metrics for each step of the horizon, aggregated over every origin. MASE is scaled
+	// using the initial window. Every metric of a step is NaN if every actual value for that step is missing
+	Horizons []metrics.Summary
+}
+
+// Backtest evaluates a prediction function using rolling-origin cross-validation. The forecast origin starts after the
+// initial window and moves forward through the series by the step, at each origin the prediction function is given
+// every observation before the origin and its forecasts for the horizon are compared with the actual values that
+// follow. Only origins with a full horizon of actual values after them are used. The origins are forecast from
+// concurrently, the first error by origin returned by the prediction function is returned, as is the context's error
+// if it is cancelled.
+// ctx - Context to cancel the backtest with
+// series - Historical seasonal data, the first value should be at the start of a season
+// config - The prediction function, its parameters and the windows to use
+func Backtest(ctx context.Context, series []float64, config BacktestConfig) (*BacktestResult, error) {
+	if config.Predict == nil {
+		return nil, &ParamError{Op: "backtest", Name: "predict function", Value: nil, Range: "provided"}
+	}
+	if config.Step < 1 {
+		return nil, &ParamError{Op: "backtest", Name: "step", Value: config.Step, Range: "at least 1"}
+	}
+	if config.Horizon < 1 {
+		return nil, &ParamError{Op: "backtest", Name: "horizon", Value: config.Horizon, Range: "at least 1"}
+	}
+	if config.InitialWindow < 1 || config.InitialWindow+config.Horizon > len(series) {
+		return nil, &ParamError{Op: "backtest", Name: "initial window", Value: config.InitialWindow, Range: fmt.Sprintf("at least 1 and leave a full horizon of %d after it in the series of length %d", config.Horizon, len(series))}
+	}
+	training := series[:config.InitialWindow]
+	seasonLength, err := resolveSeasonLength(training, config.SeasonLength)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := []int{}
+	for origin := config.InitialWindow; origin+config.Horizon <= len(series); origin += config.Step {
+		origins = append(origins, origin)
+	}
+
+	forecasts := make([][]float64, len(origins))
+	errs := make([]error, len(origins))
+	runConcurrently(ctx, len(origins), config.Workers, func(index int) {
+		forecasts[index], errs[index] = backtestOrigin(ctx, series[:origins[index]], seasonLength, config)
+	}, func(index int, err error) {
+		errs[index] = err
+	})
+
+	for i, err := range errs {
+		if err != nil && err == ctx.Err() {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to forecast from origin %d: %w", origins[i], err)
+		}
+	}
+
+	result := &BacktestResult{
+		SeasonLength: seasonLength,
+		Origins:      origins,
+		Errors:       make([][]float64, len(origins)),
+		Horizons:     make([]metrics.Summary, config.Horizon),
+	}
+	for i, origin := range origins {
+		result.Errors[i] = make([]float64, config.Horizon)
+		for step, forecast := range forecasts[i] {
+			result.Errors[i][step] = forecast - series[origin+step]
+		}
+	}
+	for step := range result.Horizons {
+		actual := make([]float64, len(origins))
+		forecast := make([]float64, len(origins))
+		for i, origin := range origins {
+			actual[i] = series[origin+step]
+			forecast[i] = forecasts[i][step]
+		}
+		summary, err := metrics.Summarise(actual, forecast, training, seasonLength)
+		if err != nil {
+			// Every actual value for the step is missing
+			nan := math.NaN()
+			summary = &metrics.Summary{MAE: nan, RMSE: nan, MAPE: nan, SMAPE: nan, MASE: nan, Bias: nan}
+		}
+		result.Horizons[step] = *summary
+	}
+	return result, nil
+}
+
+// backtestOrigin forecasts the horizon following the history provided, unless the context has already been cancelled
+func backtestOrigin(ctx context.Context, history []float64, seasonLength int, config BacktestConfig) ([]float64, error) {
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	prediction, err := config.Predict(history, seasonLength, config.Alpha, config.Beta, config.Gamma, config.Horizon)
+	if err != nil {
+		return nil, err
+	}
+	if len(prediction) != len(history)+config.Horizon {
+		return nil, fmt.Errorf("Predict function returned %d values, expected %d", len(prediction), len(history)+config.Horizon)
+	}
+	return prediction[len(history):], nil
+}
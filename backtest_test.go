@@ -0,0 +1,197 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+	"github.com/jthomperoo/holtwinters/metrics"
+)
+
+// naivePredict predicts every future value as the last value of the series
+func naivePredict(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error) {
+	result := append([]float64{}, series...)
+	for i := 0; i < predictionLength; i++ {
+		result = append(result, series[len(series)-1])
+	}
+	return result, nil
+}
+
+func TestBacktest(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var tests = []struct {
+		description string
+		expected    *holtwinters.BacktestResult
+		expectedErr error
+		ctx         context.Context
+		series      []float64
+		config      holtwinters.BacktestConfig
+	}{
+		{
+			"Fail, no predict function",
+			nil,
+			&holtwinters.ParamError{Op: "backtest", Name: "predict function", Value: nil, Range: "provided"},
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{SeasonLength: 2, InitialWindow: 3, Step: 1, Horizon: 2},
+		},
+		{
+			"Fail, step too small",
+			nil,
+			&holtwinters.ParamError{Op: "backtest", Name: "step", Value: 0, Range: "at least 1"},
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 0, Horizon: 2},
+		},
+		{
+			"Fail, horizon too small",
+			nil,
+			&holtwinters.ParamError{Op: "backtest", Name: "horizon", Value: 0, Range: "at least 1"},
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 1, Horizon: 0},
+		},
+		{
+			"Fail, no full horizon after initial window",
+			nil,
+			&holtwinters.ParamError{Op: "backtest", Name: "initial window", Value: 5, Range: "at least 1 and leave a full horizon of 2 after it in the series of length 6"},
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 5, Step: 1, Horizon: 2},
+		},
+		{
+			"Fail, predict function error",
+			nil,
+			fmt.Errorf("Failed to forecast from origin 3: %w", &holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha}),
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: holtwinters.PredictAdditive, SeasonLength: 2, Alpha: 1.5, InitialWindow: 3, Step: 1, Horizon: 2},
+		},
+		{
+			"Fail, cancelled context",
+			nil,
+			context.Canceled,
+			cancelled,
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 1, Horizon: 2, Workers: 1},
+		},
+		{
+			"Success, naive forecast, step 1",
+			&holtwinters.BacktestResult{
+				SeasonLength: 2,
+				Origins:      []int{3, 4},
+				Errors:       [][]float64{{-1, -2}, {-1, -2}},
+				Horizons: []metrics.Summary{
+					{MAE: 1, RMSE: 1, MAPE: 22.5, SMAPE: (200.0/7 + 200.0/9) / 2, MASE: 0.5, Bias: -1},
+					{MAE: 2, RMSE: 2, MAPE: (40 + 100.0/3) / 2, SMAPE: 45, MASE: 1, Bias: -2},
+				},
+			},
+			nil,
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 1, Horizon: 2, Workers: 2},
+		},
+		{
+			"Success, naive forecast, step 2 skips origins without a full horizon",
+			&holtwinters.BacktestResult{
+				SeasonLength: 2,
+				Origins:      []int{3},
+				Errors:       [][]float64{{-1, -2}},
+				Horizons: []metrics.Summary{
+					{MAE: 1, RMSE: 1, MAPE: 25, SMAPE: 200.0 / 7, MASE: 0.5, Bias: -1},
+					{MAE: 2, RMSE: 2, MAPE: 40, SMAPE: 50, MASE: 1, Bias: -2},
+				},
+			},
+			nil,
+			context.Background(),
+			[]float64{1, 2, 3, 4, 5, 6},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 2, Horizon: 2},
+		},
+		{
+			"Success, missing actual value",
+			&holtwinters.BacktestResult{
+				SeasonLength: 2,
+				Origins:      []int{3, 4},
+				Errors:       [][]float64{{-1}, {math.NaN()}},
+				Horizons: []metrics.Summary{
+					{MAE: 1, RMSE: 1, MAPE: 25, SMAPE: 200.0 / 7, MASE: 0.5, Bias: -1},
+				},
+			},
+			nil,
+			context.Background(),
+			[]float64{1, 2, 3, 4, math.NaN()},
+			holtwinters.BacktestConfig{Predict: naivePredict, SeasonLength: 2, InitialWindow: 3, Step: 1, Horizon: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.Backtest(test.ctx, test.series, test.config)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result, equateApprox, cmpopts.EquateNaNs()) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox, cmpopts.EquateNaNs()))
+			}
+		})
+	}
+}
+
+func TestBacktestMatchesPredict(t *testing.T) {
+	config := holtwinters.BacktestConfig{
+		Predict:       holtwinters.PredictMultiplicative,
+		SeasonLength:  12,
+		Alpha:         0.716,
+		Beta:          0.029,
+		Gamma:         0.993,
+		InitialWindow: 36,
+		Step:          6,
+		Horizon:       12,
+	}
+	result, err := holtwinters.Backtest(context.Background(), modelTestSeries, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedOrigins := []int{36, 42, 48, 54, 60}
+	if !cmp.Equal(expectedOrigins, result.Origins) {
+		t.Fatalf("origins mismatch (-want +got):\n%s", cmp.Diff(expectedOrigins, result.Origins))
+	}
+	for i, origin := range result.Origins {
+		prediction, err := holtwinters.PredictMultiplicative(modelTestSeries[:origin], 12, 0.716, 0.029, 0.993, 12)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := make([]float64, 12)
+		for step := range expected {
+			expected[step] = prediction[origin+step] - modelTestSeries[origin+step]
+		}
+		if !cmp.Equal(expected, result.Errors[i]) {
+			t.Errorf("errors mismatch for origin %d (-want +got):\n%s", origin, cmp.Diff(expected, result.Errors[i]))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import "context"
+
+// BatchJob is a single series to predict as part of a batch, with its own configuration
+type BatchJob struct {
+	// Key identifies the series in the results
+	Key string
+	// Series is the historical seasonal data
+	Series []float64
+	// Config is how the series is predicted, in the same way as Predict
+	Config
+}
+
+// BatchResult is the result of predicting a single series as part of a batch
+type BatchResult struct {
+	// Key identifies the series the result is for
+	Key string
+	// Prediction is the smoothed series with the predictions appended, nil if Err is set
+	Prediction []float64
+	// Err is the error predicting the series, either a parameter validation error or the context's error if the batch
+	// was cancelled before the series was predicted
+	Err error
+}
+
+// PredictBatch predicts each of the jobs provided, spreading the work over a number of goroutines. An error predicting
+// one series does not stop the others being predicted, instead it is set on that series' result. If the context is
+// cancelled, any series not yet predicted have the context's error set on their result.
+// ctx - Context to cancel the batch with
+// jobs - Series to predict, each with its own configuration
+// workers - Number of series to predict concurrently, if less than 1 runtime.GOMAXPROCS(0) is used
+// Returns a result for each job, in the same order as the jobs
+func PredictBatch(ctx context.Context, jobs []BatchJob, workers int) []BatchResult {
+	results := make([]BatchResult, len(jobs))
+	runConcurrently(ctx, len(jobs), workers, func(index int) {
+		results[index] = predictJob(ctx, jobs[index])
+	}, func(index int, err error) {
+		results[index] = BatchResult{
+			Key: jobs[index].Key,
+			Err: err,
+		}
+	})
+	return results
+}
+
+// PredictBatchMap predicts each of the jobs provided in the same way as PredictBatch, using the map keys as the jobs'
+// keys.
+// ctx - Context to cancel the batch with
+// jobs - Series to predict keyed by name, each with its own configuration
+// workers - Number of series to predict concurrently, if less than 1 runtime.GOMAXPROCS(0) is used
+// Returns the result for each job, with the same keys as the jobs
+func PredictBatchMap(ctx context.Context, jobs map[string]BatchJob, workers int) map[string]BatchResult {
+	list := make([]BatchJob, 0, len(jobs))
+	for key, job := range jobs {
+		job.Key = key
+		list = append(list, job)
+	}
+	results := make(map[string]BatchResult, len(jobs))
+	for _, result := range PredictBatch(ctx, list, workers) {
+		results[result.Key] = result
+	}
+	return results
+}
+
+// predictJob predicts a single job, unless the context has already been cancelled
+func predictJob(ctx context.Context, job BatchJob) BatchResult {
+	result := BatchResult{
+		Key: job.Key,
+	}
+	err := ctx.Err()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Prediction, result.Err = Predict(job.Series, job.Config)
+	return result
+}
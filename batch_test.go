@@ -0,0 +1,142 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestPredictBatch(t *testing.T) {
+
+	additive, err := holtwinters.PredictAdditive(modelTestSeries, 12, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplicative, err := holtwinters.PredictMultiplicative(modelTestSeries, 12, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	damped, err := holtwinters.PredictAdditiveDamped(modelTestSeries, 12, 0.716, 0.029, 0.993, 0.9, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	missingSeries := append([]float64{}, modelTestSeries...)
+	missingSeries[5] = math.NaN()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var tests = []struct {
+		description string
+		expected    []holtwinters.BatchResult
+		ctx         context.Context
+		jobs        []holtwinters.BatchJob
+		workers     int
+	}{
+		{
+			"Success, no jobs",
+			[]holtwinters.BatchResult{},
+			context.Background(),
+			[]holtwinters.BatchJob{},
+			4,
+		},
+		{
+			"Success, mixed configs and a failing series",
+			[]holtwinters.BatchResult{
+				{Key: "additive", Prediction: additive},
+				{Key: "invalid", Err: &holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha}},
+				{Key: "multiplicative", Prediction: multiplicative},
+				{Key: "damped", Prediction: damped},
+				{Key: "detected", Prediction: additive},
+				{Key: "negative", Err: &holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -1, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength}},
+				{Key: "rejected", Err: &holtwinters.ParamError{Op: "prediction", Name: "series value 5", Value: math.NaN(), Range: "finite", Err: holtwinters.ErrSeriesValue}},
+			},
+			context.Background(),
+			[]holtwinters.BatchJob{
+				{Key: "additive", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "invalid", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 1.5, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "multiplicative", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Multiplicative, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "damped", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, Phi: 0.9, PredictionLength: 24}},
+				{Key: "detected", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: holtwinters.AutoSeasonLength, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "negative", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: -1}},
+				{Key: "rejected", Series: missingSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, NonFinite: holtwinters.RejectNonFinite, PredictionLength: 24}},
+			},
+			2,
+		},
+		{
+			"Success, default workers",
+			[]holtwinters.BatchResult{
+				{Key: "a", Prediction: additive},
+				{Key: "b", Prediction: additive},
+			},
+			context.Background(),
+			[]holtwinters.BatchJob{
+				{Key: "a", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "b", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+			},
+			0,
+		},
+		{
+			"Cancelled context",
+			[]holtwinters.BatchResult{
+				{Key: "a", Err: context.Canceled},
+				{Key: "b", Err: context.Canceled},
+			},
+			cancelled,
+			[]holtwinters.BatchJob{
+				{Key: "a", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+				{Key: "b", Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 24}},
+			},
+			1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			results := holtwinters.PredictBatch(test.ctx, test.jobs, test.workers)
+			if !cmp.Equal(test.expected, results, equateErrors) {
+				t.Errorf("results mismatch (-want +got):\n%s", cmp.Diff(test.expected, results, equateErrors))
+			}
+		})
+	}
+}
+
+func TestPredictBatchMap(t *testing.T) {
+
+	additive, err := holtwinters.PredictAdditive(modelTestSeries, 12, 0.716, 0.029, 0.993, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := holtwinters.PredictBatchMap(context.Background(), map[string]holtwinters.BatchJob{
+		"valid":   {Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 12}},
+		"invalid": {Series: modelTestSeries, Config: holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 1, Alpha: 0.716, Beta: 0.029, Gamma: 0.993, PredictionLength: 12}},
+	}, 2)
+
+	expected := map[string]holtwinters.BatchResult{
+		"valid":   {Key: "valid", Prediction: additive},
+		"invalid": {Key: "invalid", Err: &holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength}},
+	}
+	if !cmp.Equal(expected, results, equateErrors) {
+		t.Errorf("results mismatch (-want +got):\n%s", cmp.Diff(expected, results, equateErrors))
+	}
+}
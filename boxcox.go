@@ -0,0 +1,350 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// guerreroLower is the smallest lambda considered by GuerreroLambda
+	guerreroLower = -1
+	// guerreroUpper is the largest lambda considered by GuerreroLambda
+	guerreroUpper = 2
+	// guerreroTolerance is the width of the interval below which the search for lambda stops
+	guerreroTolerance = 1e-6
+)
+
+// BoxCox is a Box-Cox transformation applied to a series before it is smoothed, stabilising variance that grows with
+// the level of the series, with the smoothed values and forecasts transformed back. The transformation of a value y is
+// (y^lambda - 1) / lambda, or log(y) if lambda is 0, and requires the series to be strictly positive.
+type BoxCox struct {
+	// Lambda is the transformation parameter, 0 is a log transformation and 1 only shifts the series, ignored if
+	// EstimateLambda is set
+	Lambda float64
+	// EstimateLambda chooses lambda automatically using GuerreroLambda with the season length of the series
+	EstimateLambda bool
+	// BiasAdjust adjusts forecasts transformed back to be the mean of the forecast distribution rather than the
+	// median, so that forecasts add up over time or across series, only supported for the additive method
+	BiasAdjust bool
+}
+
+// PredictAdditiveBoxCox takes in a seasonal historical series of data and produces a prediction in the same way as
+// PredictAdditive, with the series transformed using the Box-Cox transformation provided before smoothing, and the
+// smoothed values and predictions transformed back. Returns the entire dataset with the predictions appended to the
+// end.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season, must be strictly positive
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+// transform - The Box-Cox transformation to apply
+func PredictAdditiveBoxCox(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, transform BoxCox) ([]float64, error) {
+	return predictBoxCox(Additive, series, seasonLength, alpha, beta, gamma, predictionLength, transform)
+}
+
+// PredictMultiplicativeBoxCox takes in a seasonal historical series of data and produces a prediction in the same way
+// as PredictMultiplicative, with the series transformed using the Box-Cox transformation provided before smoothing,
+// and the smoothed values and predictions transformed back. Returns the entire dataset with the predictions appended
+// to the end. Bias adjustment is not supported for the multiplicative method.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season, must be strictly positive
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+// transform - The Box-Cox transformation to apply
+func PredictMultiplicativeBoxCox(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, transform BoxCox) ([]float64, error) {
+	return predictBoxCox(Multiplicative, series, seasonLength, alpha, beta, gamma, predictionLength, transform)
+}
+
+// PredictAdditiveIntervalsBoxCox takes in a seasonal historical series of data and produces predictions with
+// prediction intervals in the same way as PredictAdditiveIntervals, with the series transformed using the Box-Cox
+// transformation provided before smoothing. The intervals are calculated on the transformed scale and their bounds
+// transformed back, so they are no longer symmetric around the forecast.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season, must be strictly positive
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// predictionLength - Number of predictions to make, can't be negative
+// confidence - Confidence level of the prediction intervals, must be greater than 0 and less than 1, for example 0.95 for 95% intervals
+// transform - The Box-Cox transformation to apply
+func PredictAdditiveIntervalsBoxCox(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, confidence float64, transform BoxCox) ([]Interval, error) {
+	model, lambda, err := fitBoxCox(Additive, series, seasonLength, alpha, beta, gamma, predictionLength, transform)
+	if err != nil {
+		return nil, err
+	}
+	err = validateConfidence(confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := model.forecastIntervals(predictionLength, confidence)
+	points := model.forecast(predictionLength)
+	points = backTransform(points, model.forecastVariances(predictionLength), lambda, transform.BiasAdjust)
+	for i, interval := range intervals {
+		intervals[i] = Interval{
+			Point: points[i],
+			Lower: inverseBoxCox(interval.Lower, lambda),
+			Upper: inverseBoxCox(interval.Upper, lambda),
+		}
+	}
+	err = validateFiniteIntervals(intervals)
+	if err != nil {
+		return nil, err
+	}
+	return intervals, nil
+}
+
+// GuerreroLambda estimates the Box-Cox lambda for a series using Guerrero's method, choosing the lambda between -1 and
+// 2 that minimises the coefficient of variation of sd/mean^(1-lambda) over the subseries of the series, each a season
+// long. The subseries are taken from the end of the series, dropping any partial season at the start. Missing (NaN)
+// values are skipped.
+// series - Historical data, must be strictly positive and hold at least two full seasons
+// seasonLength - The length of the data's seasons, must be at least 2
+func GuerreroLambda(series []float64, seasonLength int) (float64, error) {
+	if seasonLength <= 1 {
+		return 0, &ParamError{Op: "Box-Cox transformation", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
+	}
+	err := validateBoxCoxSeries(series)
+	if err != nil {
+		return 0, err
+	}
+	nSeasons := len(series) / seasonLength
+	if nSeasons < 2 {
+		return 0, &ParamError{Op: "Box-Cox transformation", Name: "series length", Value: len(series), Range: fmt.Sprintf("at least 2 seasons of length %d to estimate lambda", seasonLength), Err: ErrSeriesLength}
+	}
+
+	start := len(series) - nSeasons*seasonLength
+	means := make([]float64, 0, nSeasons)
+	deviations := make([]float64, 0, nSeasons)
+	for i := 0; i < nSeasons; i++ {
+		mean, deviation := meanStandardDeviation(series[start+i*seasonLength : start+(i+1)*seasonLength])
+		if math.IsNaN(mean) || math.IsNaN(deviation) {
+			continue
+		}
+		means = append(means, mean)
+		deviations = append(deviations, deviation)
+	}
+	if len(means) < 2 {
+		return 0, &ParamError{Op: "Box-Cox transformation", Name: "seasons with 2 values present", Value: len(means), Range: "at least 2 to estimate lambda", Err: ErrSeriesValue}
+	}
+
+	ratios := make([]float64, len(means))
+	coefficientOfVariation := func(lambda float64) float64 {
+		for i := range means {
+			ratios[i] = deviations[i] / math.Pow(means[i], 1-lambda)
+		}
+		mean, deviation := meanStandardDeviation(ratios)
+		return deviation / mean
+	}
+	return goldenSectionSearch(coefficientOfVariation, guerreroLower, guerreroUpper, guerreroTolerance), nil
+}
+
+// BoxCoxTransform applies the Box-Cox transformation to each value of the series, (y^lambda - 1) / lambda, or log(y) if
+// lambda is 0. Missing (NaN) values are left as NaN.
+// series - The series to transform, must be strictly positive
+// lambda - The transformation parameter
+func BoxCoxTransform(series []float64, lambda float64) ([]float64, error) {
+	err := validateBoxCoxSeries(series)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(lambda) || math.IsInf(lambda, 0) {
+		return nil, &ParamError{Op: "Box-Cox transformation", Name: "lambda", Value: lambda, Range: "finite"}
+	}
+	result := make([]float64, len(series))
+	for i, val := range series {
+		if lambda == 0 {
+			result[i] = math.Log(val)
+			continue
+		}
+		result[i] = (math.Pow(val, lambda) - 1) / lambda
+	}
+	return result, nil
+}
+
+// InverseBoxCox reverses the Box-Cox transformation for each value of the series, (lambda*w + 1)^(1/lambda), or exp(w)
+// if lambda is 0. Values outside of the range of the transformation, where lambda*w + 1 is negative, are treated as
+// being on its boundary, giving 0 if lambda is positive or +Inf if lambda is negative.
+// series - The transformed series
+// lambda - The transformation parameter the series was transformed with
+func InverseBoxCox(series []float64, lambda float64) []float64 {
+	result := make([]float64, len(series))
+	for i, val := range series {
+		result[i] = inverseBoxCox(val, lambda)
+	}
+	return result
+}
+
+// predictBoxCox transforms the series, smooths it and makes predictions, and then transforms them back
+func predictBoxCox(method Method, series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, transform BoxCox) ([]float64, error) {
+	model, lambda, err := fitBoxCox(method, series, seasonLength, alpha, beta, gamma, predictionLength, transform)
+	if err != nil {
+		return nil, err
+	}
+	smoothed := model.fitted
+	result := make([]float64, len(smoothed), len(smoothed)+predictionLength)
+	for i, val := range smoothed {
+		result[i] = inverseBoxCox(val, lambda)
+	}
+	var variances []float64
+	if transform.BiasAdjust {
+		variances = model.forecastVariances(predictionLength)
+	}
+	result = append(result, backTransform(model.forecast(predictionLength), variances, lambda, transform.BiasAdjust)...)
+	err = validateFinite(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// boxCoxModel is a model fitted to a transformed series, along with the smoothed transformed series
+type boxCoxModel struct {
+	*Model
+	fitted []float64
+}
+
+// fitBoxCox validates the parameters, transforms the series and fits a model to it, returning the model and the lambda
+// used
+func fitBoxCox(method Method, series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, transform BoxCox) (*boxCoxModel, float64, error) {
+	seasonLength, err := resolveSeasonLength(series, seasonLength)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = validateParams(series, seasonLength, alpha, beta, gamma, predictionLength)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = validateFiniteObservations(series)
+	if err != nil {
+		return nil, 0, err
+	}
+	if transform.BiasAdjust && method != Additive {
+		return nil, 0, &ParamError{Op: "Box-Cox bias adjustment", Name: "method", Value: method, Range: "additive", Err: ErrMethod}
+	}
+	lambda := transform.Lambda
+	if transform.EstimateLambda {
+		lambda, err = GuerreroLambda(series, seasonLength)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	transformed, err := BoxCoxTransform(series, lambda)
+	if err != nil {
+		return nil, 0, err
+	}
+	model := NewModel(method, seasonLength, alpha, beta, gamma)
+	fitted := model.fit(transformed)
+	return &boxCoxModel{
+		Model:  model,
+		fitted: fitted,
+	}, lambda, nil
+}
+
+// inverseBoxCox reverses the Box-Cox transformation for a single value
+func inverseBoxCox(val float64, lambda float64) float64 {
+	if lambda == 0 {
+		return math.Exp(val)
+	}
+	return math.Pow(math.Max(lambda*val+1, 0), 1/lambda)
+}
+
+// backTransform reverses the Box-Cox transformation of forecasts, if bias adjusting using the variance of each
+// forecast on the transformed scale to give the mean of the forecast distribution rather than the median, see
+// Hyndman and Athanasopoulos, Forecasting: Principles and Practice, 3rd edition, 5.6
+func backTransform(forecasts []float64, variances []float64, lambda float64, biasAdjust bool) []float64 {
+	result := make([]float64, len(forecasts))
+	for i, val := range forecasts {
+		result[i] = inverseBoxCox(val, lambda)
+		if !biasAdjust {
+			continue
+		}
+		if lambda == 0 {
+			result[i] *= 1 + variances[i]/2
+			continue
+		}
+		base := lambda*val + 1
+		result[i] *= 1 + variances[i]*(1-lambda)/(2*base*base)
+	}
+	return result
+}
+
+// validateBoxCoxSeries ensures every value of the series that is not missing (NaN) is strictly positive
+func validateBoxCoxSeries(series []float64) error {
+	for i, val := range series {
+		if val <= 0 {
+			return &ParamError{Op: "Box-Cox transformation", Name: fmt.Sprintf("series value %d", i), Value: val, Range: "strictly positive", Err: ErrSeriesValue}
+		}
+	}
+	return nil
+}
+
+// meanStandardDeviation calculates the mean and sample standard deviation of the values, skipping missing (NaN)
+// values, the standard deviation is NaN if there are less than 2 values present
+func meanStandardDeviation(values []float64) (float64, float64) {
+	n := float64(0)
+	sum := float64(0)
+	for _, val := range values {
+		if missing(val) {
+			continue
+		}
+		n++
+		sum += val
+	}
+	if n < 2 {
+		return math.NaN(), math.NaN()
+	}
+	mean := sum / n
+	sumSquares := float64(0)
+	for _, val := range values {
+		if missing(val) {
+			continue
+		}
+		sumSquares += (val - mean) * (val - mean)
+	}
+	return mean, math.Sqrt(sumSquares / (n - 1))
+}
+
+// goldenSectionSearch finds the minimum of the function between the lower and upper bounds provided, assuming it is
+// unimodal in that range, stopping once the range is narrower than the tolerance
+func goldenSectionSearch(f func(float64) float64, lower float64, upper float64, tolerance float64) float64 {
+	ratio := (math.Sqrt(5) - 1) / 2
+	a, b := lower, upper
+	c := b - ratio*(b-a)
+	d := a + ratio*(b-a)
+	fc, fd := f(c), f(d)
+	for b-a > tolerance {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - ratio*(b-a)
+			fc = f(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + ratio*(b-a)
+			fd = f(d)
+		}
+	}
+	return (a + b) / 2
+}
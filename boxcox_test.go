@@ -0,0 +1,301 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestBoxCoxTransform(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		series      []float64
+		lambda      float64
+	}{
+		{
+			"Fail, non positive value",
+			nil,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "series value 1", Value: -1.0, Range: "strictly positive", Err: holtwinters.ErrSeriesValue},
+			[]float64{1, -1},
+			0.5,
+		},
+		{
+			"Fail, lambda not finite",
+			nil,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "lambda", Value: math.NaN(), Range: "finite"},
+			[]float64{1, 2},
+			math.NaN(),
+		},
+		{
+			"Success, log",
+			[]float64{0, math.Log(2), math.NaN()},
+			nil,
+			[]float64{1, 2, math.NaN()},
+			0,
+		},
+		{
+			"Success, square root",
+			[]float64{0, 2, 4},
+			nil,
+			[]float64{1, 4, 9},
+			0.5,
+		},
+		{
+			"Success, negative lambda",
+			[]float64{0, 0.5, 0.75},
+			nil,
+			[]float64{1, 2, 4},
+			-1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.BoxCoxTransform(test.series, test.lambda)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result, equateApprox, cmpopts.EquateNaNs()) {
+				t.Errorf("transformed mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox, cmpopts.EquateNaNs()))
+			}
+			if err != nil {
+				return
+			}
+			inverse := holtwinters.InverseBoxCox(result, test.lambda)
+			if !cmp.Equal(test.series, inverse, equateApprox, cmpopts.EquateNaNs()) {
+				t.Errorf("inverse mismatch (-want +got):\n%s", cmp.Diff(test.series, inverse, equateApprox, cmpopts.EquateNaNs()))
+			}
+		})
+	}
+}
+
+func TestInverseBoxCoxOutOfRange(t *testing.T) {
+	expected := []float64{0, math.Inf(1)}
+	result := []float64{holtwinters.InverseBoxCox([]float64{-3}, 0.5)[0], holtwinters.InverseBoxCox([]float64{2}, -1)[0]}
+	if !cmp.Equal(expected, result) {
+		t.Errorf("inverse mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+	}
+}
+
+func TestGuerreroLambda(t *testing.T) {
+	// The airline passengers reference value is from R's forecast::BoxCox.lambda using the guerrero method
+	equateReference := cmpopts.EquateApprox(0, 1e-4)
+
+	var tests = []struct {
+		description  string
+		expected     float64
+		expectedErr  error
+		series       []float64
+		seasonLength int
+	}{
+		{
+			"Fail, season length too short",
+			0,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			airlineTestSeries,
+			1,
+		},
+		{
+			"Fail, non positive value",
+			0,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "series value 0", Value: 0.0, Range: "strictly positive", Err: holtwinters.ErrSeriesValue},
+			[]float64{0, 1, 2, 3},
+			2,
+		},
+		{
+			"Fail, less than two seasons",
+			0,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "series length", Value: 20, Range: "at least 2 seasons of length 12 to estimate lambda", Err: holtwinters.ErrSeriesLength},
+			airlineTestSeries[:20],
+			12,
+		},
+		{
+			"Success, airline passengers",
+			-0.2947156,
+			nil,
+			airlineTestSeries,
+			12,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.GuerreroLambda(test.series, test.seasonLength)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result, equateReference) {
+				t.Errorf("lambda mismatch, want %f, got %f", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestPredictBoxCox(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+
+	additive, err := holtwinters.PredictAdditive(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplicative, err := holtwinters.PredictMultiplicative(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		predict     func([]float64, int, float64, float64, float64, int, holtwinters.BoxCox) ([]float64, error)
+		series      []float64
+		transform   holtwinters.BoxCox
+	}{
+		{
+			"Fail, invalid parameter",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			func(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, transform holtwinters.BoxCox) ([]float64, error) {
+				return holtwinters.PredictAdditiveBoxCox(series, seasonLength, 1.5, beta, gamma, predictionLength, transform)
+			},
+			airlineTestSeries,
+			holtwinters.BoxCox{Lambda: 0},
+		},
+		{
+			"Fail, non positive value",
+			nil,
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "series value 0", Value: -1.0, Range: "strictly positive", Err: holtwinters.ErrSeriesValue},
+			holtwinters.PredictAdditiveBoxCox,
+			append([]float64{-1}, airlineTestSeries[1:]...),
+			holtwinters.BoxCox{Lambda: 0},
+		},
+		{
+			"Fail, multiplicative bias adjustment",
+			nil,
+			&holtwinters.ParamError{Op: "Box-Cox bias adjustment", Name: "method", Value: holtwinters.Multiplicative, Range: "additive", Err: holtwinters.ErrMethod},
+			holtwinters.PredictMultiplicativeBoxCox,
+			airlineTestSeries,
+			holtwinters.BoxCox{Lambda: 0, BiasAdjust: true},
+		},
+		{
+			"Success, additive with lambda of 1 is the same as no transformation",
+			additive,
+			nil,
+			holtwinters.PredictAdditiveBoxCox,
+			airlineTestSeries,
+			holtwinters.BoxCox{Lambda: 1},
+		},
+		{
+			"Success, additive with lambda of 1 and bias adjustment is the same as no transformation",
+			additive,
+			nil,
+			holtwinters.PredictAdditiveBoxCox,
+			airlineTestSeries,
+			holtwinters.BoxCox{Lambda: 1, BiasAdjust: true},
+		},
+		{
+			"Success, multiplicative with lambda of 1",
+			nil,
+			nil,
+			holtwinters.PredictMultiplicativeBoxCox,
+			airlineTestSeries,
+			holtwinters.BoxCox{Lambda: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.predict(test.series, 12, 0.5, 0.05, 0.3, 12, test.transform)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if test.expected != nil && !cmp.Equal(test.expected, result, equateApprox) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox))
+			}
+			if err == nil && len(result) != len(test.series)+12 {
+				t.Errorf("prediction length mismatch, want %d, got %d", len(test.series)+12, len(result))
+			}
+		})
+	}
+
+	// The multiplicative method is not shift invariant, so a lambda of 1 only gives close results
+	result, err := holtwinters.PredictMultiplicativeBoxCox(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, holtwinters.BoxCox{Lambda: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	equateClose := cmpopts.EquateApprox(0.05, 0)
+	if !cmp.Equal(multiplicative, result, equateClose) {
+		t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(multiplicative, result, equateClose))
+	}
+}
+
+func TestPredictAdditiveIntervalsBoxCox(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+
+	// A lambda of 1 only shifts the series, so the intervals are the same as without a transformation
+	expected, err := holtwinters.PredictAdditiveIntervals(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, 0.95)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := holtwinters.PredictAdditiveIntervalsBoxCox(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, 0.95, holtwinters.BoxCox{Lambda: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(expected, result, equateApprox) {
+		t.Errorf("intervals mismatch (-want +got):\n%s", cmp.Diff(expected, result, equateApprox))
+	}
+
+	// With a log transformation the intervals are symmetric on the log scale around the median forecast, and the bias
+	// adjusted forecast is the median multiplied by 1 + variance/2
+	median, err := holtwinters.PredictAdditiveIntervalsBoxCox(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, 0.95, holtwinters.BoxCox{Lambda: 0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mean, err := holtwinters.PredictAdditiveIntervalsBoxCox(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, 0.95, holtwinters.BoxCox{Lambda: 0, BiasAdjust: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	z := math.Sqrt2 * math.Erfinv(0.95)
+	for i := range median {
+		upper := math.Log(median[i].Upper) - math.Log(median[i].Point)
+		lower := math.Log(median[i].Point) - math.Log(median[i].Lower)
+		if !cmp.Equal(upper, lower, equateApprox) {
+			t.Errorf("interval %d not symmetric on the log scale, upper: %f, lower: %f", i, upper, lower)
+		}
+		variance := (upper / z) * (upper / z)
+		expected := holtwinters.Interval{Point: median[i].Point * (1 + variance/2), Lower: median[i].Lower, Upper: median[i].Upper}
+		if !cmp.Equal(expected, mean[i], equateApprox) {
+			t.Errorf("bias adjusted interval %d mismatch (-want +got):\n%s", i, cmp.Diff(expected, mean[i], equateApprox))
+		}
+	}
+
+	// The confidence is validated before lambda is estimated
+	_, err = holtwinters.PredictAdditiveIntervalsBoxCox(airlineTestSeries, 12, 0.5, 0.05, 0.3, 12, 1.5, holtwinters.BoxCox{EstimateLambda: true})
+	expectedErr := &holtwinters.ParamError{Op: "prediction", Name: "confidence", Value: 1.5, Range: "greater than 0 and less than 1", Err: holtwinters.ErrConfidence}
+	if !cmp.Equal(error(expectedErr), err, equateErrors) {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+	}
+}
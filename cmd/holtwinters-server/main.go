@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command holtwinters-server serves Holt-Winters predictions over HTTP, so services not written in Go can use them.
+//
+// Endpoints:
+//
+//	POST /predict - takes a JSON prediction request and returns the smoothed series with predictions appended
+//	GET /healthz - returns 200 while the server is running
+//	GET /readyz - returns 200 while the server is ready to take requests, 503 once it is shutting down
+//
+// Usage:
+//
+//	holtwinters-server [-addr :8080] [-max-series-length 100000] [-max-prediction-length 10000] [-handler-timeout 10s]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxBodyBytes := flag.Int64("max-body-bytes", 10<<20, "maximum size of a request body in bytes")
+	maxSeriesLength := flag.Int("max-series-length", 100000, "maximum length of a request's series")
+	maxSeasonLength := flag.Int("max-season-length", 10080, "longest season length considered when detecting the season length, at least 2")
+	maxPredictionLength := flag.Int("max-prediction-length", 10000, "maximum number of predictions a request can make")
+	readHeaderTimeout := flag.Duration("read-header-timeout", 5*time.Second, "time allowed to read a request's headers")
+	readTimeout := flag.Duration("read-timeout", 30*time.Second, "time allowed to read an entire request, including the body")
+	handlerTimeout := flag.Duration("handler-timeout", 10*time.Second, "time allowed to make a prediction once a request has been read")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "time to wait for requests to finish when shutting down")
+	flag.Parse()
+
+	if *maxSeasonLength < 2 {
+		log.Fatalf("max season length must be at least 2, is %d", *maxSeasonLength)
+	}
+
+	srv := newServer(limits{
+		maxBodyBytes:        *maxBodyBytes,
+		maxSeriesLength:     *maxSeriesLength,
+		maxSeasonLength:     *maxSeasonLength,
+		maxPredictionLength: *maxPredictionLength,
+		timeout:             *handlerTimeout,
+	})
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: *readHeaderTimeout,
+		ReadTimeout:       *readTimeout,
+	}
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-shutdown
+		srv.setReady(false)
+		ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(ctx)
+		if err != nil {
+			log.Printf("failed to shut down gracefully: %s", err)
+		}
+	}()
+
+	log.Printf("listening on %s", *addr)
+	srv.setReady(true)
+	err := httpServer.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/jthomperoo/holtwinters"
+)
+
+const (
+	// codeInvalidRequest is the error code for requests that cannot be parsed
+	codeInvalidRequest = "invalid_request"
+	// codeInvalidParameter is the error code for requests with parameters that fail validation
+	codeInvalidParameter = "invalid_parameter"
+	// codeMethodNotAllowed is the error code for requests using the wrong HTTP method
+	codeMethodNotAllowed = "method_not_allowed"
+	// codeTimeout is the error code for requests that take too long to handle
+	codeTimeout = "timeout"
+	// codeInternal is the error code for unexpected failures
+	codeInternal = "internal"
+)
+
+// predictRequest is the body of a prediction request
+type predictRequest struct {
+	// Series is the historical data, null values are treated as missing
+	Series []*float64 `json:"series"`
+	// Method is additive or multiplicative, defaults to additive
+	Method string `json:"method"`
+	// SeasonLength is the length of the data's seasons, detected from the series if not provided
+	SeasonLength *int `json:"seasonLength"`
+	// Alpha is the exponential smoothing coefficient for level
+	Alpha float64 `json:"alpha"`
+	// Beta is the exponential smoothing coefficient for trend
+	Beta float64 `json:"beta"`
+	// Gamma is the exponential smoothing coefficient for seasonality
+	Gamma float64 `json:"gamma"`
+	// PredictionLength is the number of predictions to make
+	PredictionLength int `json:"predictionLength"`
+}
+
+// predictResponse is the body of a successful prediction response
+type predictResponse struct {
+	// Prediction is the smoothed series with the predictions appended
+	Prediction []float64 `json:"prediction"`
+	// SeasonLength is the season length used, which may have been detected from the series
+	SeasonLength int `json:"seasonLength"`
+}
+
+// errorResponse is the body of an error response
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes why a request failed
+type errorDetail struct {
+	// Code identifies the kind of failure
+	Code string `json:"code"`
+	// Message is a human readable description of the failure
+	Message string `json:"message"`
+	// Parameter is the name of the invalid parameter, only provided for invalid parameter failures
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// limits bound the resources a single prediction request can use
+type limits struct {
+	// maxBodyBytes is the maximum size of a request body in bytes
+	maxBodyBytes int64
+	// maxSeriesLength is the maximum length of a request's series
+	maxSeriesLength int
+	// maxSeasonLength is the longest season length considered when detecting the season length of a series
+	maxSeasonLength int
+	// maxPredictionLength is the maximum number of predictions a request can make
+	maxPredictionLength int
+	// timeout is the time allowed to handle a request once it has been read
+	timeout time.Duration
+}
+
+// server handles prediction, health and readiness requests
+type server struct {
+	mux    *http.ServeMux
+	limits limits
+	ready  int32
+}
+
+// newServer creates a new server, which is not ready until setReady is called
+func newServer(limits limits) *server {
+	srv := &server{
+		mux:    http.NewServeMux(),
+		limits: limits,
+	}
+	srv.mux.Handle("/predict", withTimeout(http.HandlerFunc(srv.handlePredict), limits.timeout))
+	srv.mux.HandleFunc("/healthz", srv.handleHealth)
+	srv.mux.HandleFunc("/readyz", srv.handleReady)
+	return srv
+}
+
+// withTimeout responds with a 503 timeout error if the handler takes longer than the timeout to respond. The handler
+// is left to finish in the background with its response discarded, so the work it can do must be bounded separately
+func withTimeout(handler http.Handler, timeout time.Duration) http.Handler {
+	body, _ := json.Marshal(errorResponse{
+		Error: errorDetail{
+			Code:    codeTimeout,
+			Message: fmt.Sprintf("request took longer than %s to handle", timeout),
+		},
+	})
+	timeoutHandler := http.TimeoutHandler(handler, timeout, string(body))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only used by the timeout response, the handler's own headers replace it otherwise
+		w.Header().Set("Content-Type", "application/json")
+		timeoutHandler.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP implements http.Handler
+func (srv *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.mux.ServeHTTP(w, r)
+}
+
+// setReady sets whether the server reports that it is ready to take requests
+func (srv *server) setReady(ready bool) {
+	var val int32
+	if ready {
+		val = 1
+	}
+	atomic.StoreInt32(&srv.ready, val)
+}
+
+// handleHealth reports that the server is running
+func (srv *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether the server is ready to take requests
+func (srv *server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&srv.ready) == 0 {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// handlePredict parses a prediction request and responds with the prediction
+func (srv *server) handlePredict(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, fmt.Sprintf("method %s not allowed, use POST", r.Method))
+		return
+	}
+
+	request := predictRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, srv.limits.maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, fmt.Sprintf("failed to parse request body: %s", err))
+		return
+	}
+
+	var predict func(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error)
+	switch request.Method {
+	case "", "additive":
+		predict = holtwinters.PredictAdditive
+	case "multiplicative":
+		predict = holtwinters.PredictMultiplicative
+	default:
+		writeParamError(w, &holtwinters.ParamError{
+			Op:    "prediction",
+			Name:  "method",
+			Value: fmt.Sprintf("%q", request.Method),
+			Range: "additive or multiplicative",
+			Err:   holtwinters.ErrMethod,
+		})
+		return
+	}
+
+	// The body size limit does not bound the predictions, so a small request could otherwise allocate any amount
+	if request.PredictionLength > srv.limits.maxPredictionLength {
+		writeParamError(w, &holtwinters.ParamError{
+			Op:    "prediction",
+			Name:  "prediction length",
+			Value: request.PredictionLength,
+			Range: fmt.Sprintf("at most %d", srv.limits.maxPredictionLength),
+			Err:   holtwinters.ErrPredictionLength,
+		})
+		return
+	}
+	if len(request.Series) > srv.limits.maxSeriesLength {
+		writeParamError(w, &holtwinters.ParamError{
+			Op:    "prediction",
+			Name:  "series length",
+			Value: len(request.Series),
+			Range: fmt.Sprintf("at most %d", srv.limits.maxSeriesLength),
+			Err:   holtwinters.ErrSeriesLength,
+		})
+		return
+	}
+
+	series := make([]float64, len(request.Series))
+	for i, val := range request.Series {
+		series[i] = math.NaN()
+		if val != nil {
+			series[i] = *val
+		}
+	}
+
+	seasonLength := holtwinters.AutoSeasonLength
+	if request.SeasonLength != nil {
+		seasonLength = *request.SeasonLength
+	}
+	if seasonLength == holtwinters.AutoSeasonLength {
+		// Detected here rather than by the prediction function so that the longest season length considered is bounded
+		candidates, err := holtwinters.DetectSeasonLength(series, srv.limits.maxSeasonLength)
+		if err != nil {
+			writeParamError(w, err)
+			return
+		}
+		if len(candidates) == 0 || !candidates[0].Significant {
+			writeParamError(w, &holtwinters.ParamError{
+				Op:    "prediction",
+				Name:  "season length",
+				Value: "AutoSeasonLength",
+				Range: fmt.Sprintf("provided, as no season length up to %d could be detected from the series", srv.limits.maxSeasonLength),
+				Err:   holtwinters.ErrSeasonLength,
+			})
+			return
+		}
+		seasonLength = candidates[0].SeasonLength
+	}
+
+	// The prediction functions only return errors caused by the request, parameter validation errors or a series unsuitable
+	// for the method
+	prediction, err := predict(series, seasonLength, request.Alpha, request.Beta, request.Gamma, request.PredictionLength)
+	if err != nil {
+		writeParamError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, predictResponse{
+		Prediction:   prediction,
+		SeasonLength: seasonLength,
+	})
+}
+
+// writeError writes a structured error response
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, errorResponse{
+		Error: errorDetail{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
+
+// writeParamError writes an invalid parameter error response, including the name of the parameter if the error is a
+// ParamError
+func writeParamError(w http.ResponseWriter, err error) {
+	detail := errorDetail{
+		Code:    codeInvalidParameter,
+		Message: err.Error(),
+	}
+	var paramErr *holtwinters.ParamError
+	if errors.As(err, &paramErr) {
+		detail.Parameter = paramErr.Name
+	}
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: detail})
+}
+
+// writeJSON writes a JSON response with the status provided
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		log.Printf("failed to encode response: %s", err)
+		status = http.StatusInternalServerError
+		encoded, _ = json.Marshal(errorResponse{
+			Error: errorDetail{
+				Code:    codeInternal,
+				Message: "failed to encode response",
+			},
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(encoded)
+	if err != nil {
+		log.Printf("failed to write response: %s", err)
+	}
+}
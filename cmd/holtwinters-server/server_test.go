@@ -0,0 +1,276 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+// mustMarshal marshals the value provided to a JSON string, failing the test if it can't be marshalled
+func mustMarshal(t *testing.T, value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return string(encoded)
+}
+
+// mustPredict makes a prediction, failing the test if it returns an error
+func mustPredict(t *testing.T, predict func([]float64, int, float64, float64, float64, int) ([]float64, error), series []float64, seasonLength int, predictionLength int) []float64 {
+	prediction, err := predict(series, seasonLength, 0.5, 0.1, 0.1, predictionLength)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return prediction
+}
+
+func TestServer(t *testing.T) {
+	series := []float64{}
+	for i := 0; i < 28; i++ {
+		series = append(series, 10+0.5*float64(i)+3*math.Sin(2*math.Pi*float64(i)/7))
+	}
+	seriesJSON := mustMarshal(t, series)
+	missingSeries := append([]float64{}, series...)
+	missingSeries[3] = math.NaN()
+	missingSeriesJSON := strings.Replace(seriesJSON, mustMarshal(t, series[3]), "null", 1)
+	longSeason := []float64{}
+	for i := 0; i < 48; i++ {
+		longSeason = append(longSeason, float64(i%16))
+	}
+	longSeasonJSON := mustMarshal(t, longSeason)
+
+	var tests = []struct {
+		description      string
+		expectedStatus   int
+		expectedResponse interface{}
+		method           string
+		path             string
+		body             string
+		ready            bool
+	}{
+		{
+			"Health",
+			http.StatusOK,
+			map[string]string{"status": "ok"},
+			http.MethodGet,
+			"/healthz",
+			"",
+			false,
+		},
+		{
+			"Ready",
+			http.StatusOK,
+			map[string]string{"status": "ready"},
+			http.MethodGet,
+			"/readyz",
+			"",
+			true,
+		},
+		{
+			"Not ready",
+			http.StatusServiceUnavailable,
+			map[string]string{"status": "not ready"},
+			http.MethodGet,
+			"/readyz",
+			"",
+			false,
+		},
+		{
+			"Predict, wrong HTTP method",
+			http.StatusMethodNotAllowed,
+			errorResponse{Error: errorDetail{Code: codeMethodNotAllowed, Message: "method GET not allowed, use POST"}},
+			http.MethodGet,
+			"/predict",
+			"",
+			true,
+		},
+		{
+			"Predict, invalid JSON",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidRequest, Message: "failed to parse request body: unexpected EOF"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2`,
+			true,
+		},
+		{
+			"Predict, unknown field",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidRequest, Message: `failed to parse request body: json: unknown field "horizon"`}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2, 3], "horizon": 5}`,
+			true,
+		},
+		{
+			"Predict, body too large",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidRequest, Message: "failed to parse request body: http: request body too large"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [` + strings.Repeat("1, ", 1000) + `1]}`,
+			true,
+		},
+		{
+			"Predict, unknown method",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: `Invalid parameter for prediction; method must be additive or multiplicative, is "exponential"`, Parameter: "method"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2, 3, 4], "method": "exponential", "seasonLength": 2}`,
+			true,
+		},
+		{
+			"Predict, invalid alpha",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; alpha must be between 0 and 1, is 1.500000", Parameter: "alpha"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2, 3, 4], "seasonLength": 2, "alpha": 1.5}`,
+			true,
+		},
+		{
+			"Predict, series too short",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; series length must be at least the season length 4, is 3", Parameter: "series length"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2, 3], "seasonLength": 4}`,
+			true,
+		},
+		{
+			"Predict, prediction length too long",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; prediction length must be at most 100, is 2000000000", Parameter: "prediction length"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": ` + seriesJSON + `, "seasonLength": 7, "predictionLength": 2000000000}`,
+			true,
+		},
+		{
+			"Predict, series too long",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; series length must be at most 50, is 51", Parameter: "series length"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [` + strings.Repeat("1, ", 50) + `1], "seasonLength": 2}`,
+			true,
+		},
+		{
+			"Predict, season length longer than the max not detected",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; season length must be provided, as no season length up to 10 could be detected from the series, is AutoSeasonLength", Parameter: "season length"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": ` + longSeasonJSON + `}`,
+			true,
+		},
+		{
+			"Predict, season length not detected",
+			http.StatusBadRequest,
+			errorResponse{Error: errorDetail{Code: codeInvalidParameter, Message: "Invalid parameter for prediction; season length must be provided, as no season length up to 10 could be detected from the series, is AutoSeasonLength", Parameter: "season length"}},
+			http.MethodPost,
+			"/predict",
+			`{"series": [1, 2, 3, 4, 5, 6, 7, 8, 9, 10]}`,
+			true,
+		},
+		{
+			"Predict, additive",
+			http.StatusOK,
+			predictResponse{Prediction: mustPredict(t, holtwinters.PredictAdditive, series, 7, 7), SeasonLength: 7},
+			http.MethodPost,
+			"/predict",
+			`{"series": ` + seriesJSON + `, "method": "additive", "seasonLength": 7, "alpha": 0.5, "beta": 0.1, "gamma": 0.1, "predictionLength": 7}`,
+			true,
+		},
+		{
+			"Predict, multiplicative",
+			http.StatusOK,
+			predictResponse{Prediction: mustPredict(t, holtwinters.PredictMultiplicative, series, 7, 7), SeasonLength: 7},
+			http.MethodPost,
+			"/predict",
+			`{"series": ` + seriesJSON + `, "method": "multiplicative", "seasonLength": 7, "alpha": 0.5, "beta": 0.1, "gamma": 0.1, "predictionLength": 7}`,
+			true,
+		},
+		{
+			"Predict, detected season length and missing values",
+			http.StatusOK,
+			predictResponse{Prediction: mustPredict(t, holtwinters.PredictAdditive, missingSeries, 7, 3), SeasonLength: 7},
+			http.MethodPost,
+			"/predict",
+			`{"series": ` + missingSeriesJSON + `, "alpha": 0.5, "beta": 0.1, "gamma": 0.1, "predictionLength": 3}`,
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			srv := newServer(limits{
+				maxBodyBytes:        1024,
+				maxSeriesLength:     50,
+				maxSeasonLength:     10,
+				maxPredictionLength: 100,
+				timeout:             time.Minute,
+			})
+			srv.setReady(test.ready)
+
+			recorder := httptest.NewRecorder()
+			srv.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("status mismatch, want %d, got %d", test.expectedStatus, recorder.Code)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("content type mismatch, want application/json, got %s", contentType)
+			}
+			expected := mustMarshal(t, test.expectedResponse)
+			if !cmp.Equal(expected, recorder.Body.String()) {
+				t.Errorf("response mismatch (-want +got):\n%s", cmp.Diff(expected, recorder.Body.String()))
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	handler := withTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}), time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/predict", strings.NewReader("{}")))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("status mismatch, want %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("content type mismatch, want application/json, got %s", contentType)
+	}
+	expected := mustMarshal(t, errorResponse{Error: errorDetail{Code: codeTimeout, Message: "request took longer than 1ms to handle"}})
+	if !cmp.Equal(expected, recorder.Body.String()) {
+		t.Errorf("response mismatch (-want +got):\n%s", cmp.Diff(expected, recorder.Body.String()))
+	}
+}
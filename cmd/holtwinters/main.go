@@ -0,0 +1,336 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command holtwinters reads a series from a file or stdin and writes out the series smoothed using Holt-Winters
+// exponential smoothing, followed by predictions.
+//
+// The series can be provided as CSV, newline separated numbers or a JSON array of numbers. The output is CSV or JSON,
+// with each row holding the index, the value and whether the value is a prediction.
+//
+// Usage:
+//
+//	holtwinters [flags] [file]
+//
+// If no file is provided, or the file is -, the series is read from stdin. Run holtwinters -h for the flags.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jthomperoo/holtwinters"
+)
+
+const (
+	formatAuto  = "auto"
+	formatCSV   = "csv"
+	formatLines = "lines"
+	formatJSON  = "json"
+)
+
+// row is a single value in the output
+type row struct {
+	Index    int     `json:"index"`
+	Value    float64 `json:"value"`
+	Forecast bool    `json:"forecast"`
+}
+
+// usageError is returned by run when the command line is invalid, after reporting it along with the usage
+type usageError struct {
+	err error
+}
+
+// Error returns the reason the command line is invalid
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the reason the command line is invalid
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	code := exitCode(err)
+	if code == 1 {
+		fmt.Fprintf(os.Stderr, "holtwinters: %s\n", err)
+	}
+	os.Exit(code)
+}
+
+// exitCode returns the status to exit with for the error returned by run, 0 for success or if help was requested, 2
+// for an invalid command line in the same way as the flag package, and 1 for any other failure
+func exitCode(err error) int {
+	var usage *usageError
+	switch {
+	case err == nil, err == flag.ErrHelp:
+		return 0
+	case errors.As(err, &usage):
+		return 2
+	}
+	return 1
+}
+
+// run parses the arguments, reads the series, makes the prediction and writes it out
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("holtwinters", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	method := flags.String("method", "additive", "Holt-Winters method to use, additive or multiplicative")
+	seasonLength := flags.String("season-length", "auto", "length of the data's seasons, at least 2, or auto to detect it from the series")
+	alpha := flags.Float64("alpha", 0.5, "exponential smoothing coefficient for level, between 0 and 1")
+	beta := flags.Float64("beta", 0.1, "exponential smoothing coefficient for trend, between 0 and 1")
+	gamma := flags.Float64("gamma", 0.1, "exponential smoothing coefficient for seasonality, between 0 and 1")
+	horizon := flags.Int("horizon", 0, "number of predictions to make")
+	inputFormat := flags.String("input-format", formatAuto, "format of the input, auto, csv, lines or json")
+	column := flags.Int("column", 0, "index of the CSV column holding the series, starting from 0")
+	outputFormat := flags.String("output-format", formatCSV, "format of the output, csv or json")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: holtwinters [flags] [file]\n\nReads the series from stdin if no file is provided or the file is -.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return err
+	}
+	if err != nil {
+		// The flag set has already reported the error along with the usage
+		return &usageError{err: err}
+	}
+	// invalid reports an invalid flag value or argument along with the usage, in the same way as the flag set
+	invalid := func(err error) error {
+		fmt.Fprintln(stderr, err)
+		flags.Usage()
+		return &usageError{err: err}
+	}
+	if flags.NArg() > 1 {
+		return invalid(fmt.Errorf("expected at most one input file, got %d", flags.NArg()))
+	}
+
+	length := holtwinters.AutoSeasonLength
+	if *seasonLength != "auto" {
+		length, err = strconv.Atoi(*seasonLength)
+		if err != nil {
+			return invalid(fmt.Errorf("invalid season length %q, must be an integer or auto", *seasonLength))
+		}
+	}
+
+	var predict func(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error)
+	switch *method {
+	case "additive":
+		predict = holtwinters.PredictAdditive
+	case "multiplicative":
+		predict = holtwinters.PredictMultiplicative
+	default:
+		return invalid(fmt.Errorf("unknown method %q, must be additive or multiplicative", *method))
+	}
+
+	switch *inputFormat {
+	case formatAuto, formatCSV, formatLines, formatJSON:
+	default:
+		return invalid(fmt.Errorf("unknown input format %q, must be auto, csv, lines or json", *inputFormat))
+	}
+
+	if *outputFormat != formatCSV && *outputFormat != formatJSON {
+		return invalid(fmt.Errorf("unknown output format %q, must be csv or json", *outputFormat))
+	}
+
+	input := stdin
+	if flags.NArg() == 1 && flags.Arg(0) != "-" {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	series, err := readSeries(input, *inputFormat, *column)
+	if err != nil {
+		return err
+	}
+
+	prediction, err := predict(series, length, *alpha, *beta, *gamma, *horizon)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]row, len(prediction))
+	for i, val := range prediction {
+		rows[i] = row{
+			Index:    i,
+			Value:    val,
+			Forecast: i >= len(series),
+		}
+	}
+	return writeRows(stdout, rows, *outputFormat)
+}
+
+// readSeries reads a series in the format provided, detecting the format from the content if it is auto. In JSON
+// input null values are treated as missing
+func readSeries(input io.Reader, format string, column int) ([]float64, error) {
+	reader := bufio.NewReader(input)
+	if format == formatAuto {
+		format = detectFormat(reader)
+	}
+	switch format {
+	case formatJSON:
+		// Decode as pointers so null values can be treated as missing
+		values := []*float64{}
+		err := json.NewDecoder(reader).Decode(&values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON input: %s", err)
+		}
+		series := make([]float64, len(values))
+		for i, val := range values {
+			series[i] = math.NaN()
+			if val != nil {
+				series[i] = *val
+			}
+		}
+		return series, nil
+	case formatCSV:
+		return readCSV(reader, column)
+	case formatLines:
+		return readLines(reader)
+	}
+	return nil, fmt.Errorf("unknown input format %q, must be auto, csv, lines or json", format)
+}
+
+// detectFormat guesses the format of the input from its first non whitespace characters, without consuming them
+func detectFormat(reader *bufio.Reader) string {
+	for size := 64; ; size *= 2 {
+		peeked, err := reader.Peek(size)
+		trimmed := strings.TrimSpace(string(peeked))
+		if strings.HasPrefix(trimmed, "[") {
+			return formatJSON
+		}
+		line := strings.SplitN(trimmed, "\n", 2)
+		if len(line) > 1 || err != nil {
+			if strings.Contains(line[0], ",") {
+				return formatCSV
+			}
+			return formatLines
+		}
+	}
+}
+
+// readCSV reads the series from a column of CSV, skipping a header row if the first row is not numeric. Empty values
+// and NaN are treated as missing
+func readCSV(reader io.Reader, column int) ([]float64, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV input: %s", err)
+	}
+	series := []float64{}
+	for i, record := range records {
+		if column < 0 || column >= len(record) {
+			return nil, fmt.Errorf("CSV row %d has no column %d", i+1, column)
+		}
+		val, err := parseValue(record[column])
+		if err != nil {
+			if i == 0 {
+				// Header row
+				continue
+			}
+			return nil, fmt.Errorf("CSV row %d: %s", i+1, err)
+		}
+		series = append(series, val)
+	}
+	return series, nil
+}
+
+// readLines reads the series as one number per line, skipping blank lines and a header line if the first line is not
+// numeric, NaN is treated as missing
+func readLines(reader io.Reader) ([]float64, error) {
+	series := []float64{}
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	header := false
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		val, err := parseValue(text)
+		if err != nil {
+			if len(series) == 0 && !header {
+				// Header line
+				header = true
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		series = append(series, val)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// parseValue parses a single value of the series, an empty value is treated as missing
+func parseValue(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return math.NaN(), nil
+	}
+	val, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	return val, nil
+}
+
+// writeRows writes the rows out in the format provided
+func writeRows(output io.Writer, rows []row, format string) error {
+	if format == formatJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	writer := csv.NewWriter(output)
+	err := writer.Write([]string{"index", "value", "forecast"})
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		err = writer.Write([]string{
+			strconv.Itoa(r.Index),
+			strconv.FormatFloat(r.Value, 'g', -1, 64),
+			strconv.FormatBool(r.Forecast),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV output: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestRun(t *testing.T) {
+	equateErrors := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		xParam, xIsParam := x.(*holtwinters.ParamError)
+		yParam, yIsParam := y.(*holtwinters.ParamError)
+		if xIsParam || yIsParam {
+			return xIsParam && yIsParam && xParam.Op == yParam.Op && xParam.Name == yParam.Name &&
+				xParam.Range == yParam.Range && xParam.Err == yParam.Err && cmp.Equal(xParam.Value, yParam.Value)
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description string
+		expected    string
+		expectedErr error
+		args        []string
+		input       string
+	}{
+		{
+			"Fail, unknown method",
+			"",
+			errors.New(`unknown method "exponential", must be additive or multiplicative`),
+			[]string{"-method", "exponential"},
+			"1\n2\n3\n",
+		},
+		{
+			"Fail, invalid season length",
+			"",
+			errors.New(`invalid season length "five", must be an integer or auto`),
+			[]string{"-season-length", "five"},
+			"1\n2\n3\n",
+		},
+		{
+			"Fail, unknown output format",
+			"",
+			errors.New(`unknown output format "xml", must be csv or json`),
+			[]string{"-output-format", "xml"},
+			"1\n2\n3\n",
+		},
+		{
+			"Fail, unknown input format",
+			"",
+			errors.New(`unknown input format "xml", must be auto, csv, lines or json`),
+			[]string{"-input-format", "xml"},
+			"1\n2\n3\n",
+		},
+		{
+			"Fail, invalid number",
+			"",
+			errors.New(`line 3: invalid number "three"`),
+			[]string{"-season-length", "2"},
+			"1\n2\nthree\n",
+		},
+		{
+			"Fail, missing CSV column",
+			"",
+			errors.New(`CSV row 1 has no column 2`),
+			[]string{"-season-length", "2", "-column", "2"},
+			"a,1\nb,2\n",
+		},
+		{
+			"Fail, invalid JSON",
+			"",
+			errors.New(`failed to parse JSON input: unexpected EOF`),
+			[]string{"-season-length", "2"},
+			"[1, 2",
+		},
+		{
+			"Fail, prediction validation error",
+			"",
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.5, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			[]string{"-season-length", "5", "-alpha", "1.5"},
+			"1\n2\n3\n2\n1\n",
+		},
+		{
+			"Success, lines input, CSV output",
+			`index,value,forecast
+0,1,false
+1,2.8400000000000003,false
+2,3.1516,false
+3,1.959964,false
+4,0.9732295599999999,false
+5,0.971479762,true
+6,1.926903744,true
+`,
+			nil,
+			[]string{"-season-length", "5", "-alpha", "0.9", "-beta", "0.9", "-gamma", "0.9", "-horizon", "2"},
+			"1\n2\n3\n2\n1\n",
+		},
+		{
+			"Success, CSV input with header, CSV output",
+			`index,value,forecast
+0,1,false
+1,2.8400000000000003,false
+2,3.1516,false
+3,1.959964,false
+4,0.9732295599999999,false
+5,0.971479762,true
+`,
+			nil,
+			[]string{"-season-length", "5", "-alpha", "0.9", "-beta", "0.9", "-gamma", "0.9", "-horizon", "1", "-column", "1"},
+			"time,value\n00:00,1\n00:01,2\n00:02,3\n00:03,2\n00:04,1\n",
+		},
+		{
+			"Success, JSON input, JSON output",
+			`[
+  {
+    "index": 0,
+    "value": 1,
+    "forecast": false
+  },
+  {
+    "index": 1,
+    "value": 2.8400000000000003,
+    "forecast": false
+  },
+  {
+    "index": 2,
+    "value": 3.1516,
+    "forecast": false
+  },
+  {
+    "index": 3,
+    "value": 1.959964,
+    "forecast": false
+  },
+  {
+    "index": 4,
+    "value": 0.9732295599999999,
+    "forecast": false
+  },
+  {
+    "index": 5,
+    "value": 0.971479762,
+    "forecast": true
+  }
+]
+`,
+			nil,
+			[]string{"-season-length", "5", "-alpha", "0.9", "-beta", "0.9", "-gamma", "0.9", "-horizon", "1", "-output-format", "json"},
+			" [1, 2, 3, 2, 1]",
+		},
+		{
+			"Success, multiplicative, explicit stdin",
+			`index,value,forecast
+0,1,false
+1,2.6875333333333327,false
+2,2.3990147705041394,false
+3,1.777577751040615,false
+4,0.9938269610471768,false
+`,
+			nil,
+			[]string{"-method", "multiplicative", "-season-length", "5", "-alpha", "0.9", "-beta", "0.9", "-gamma", "0.9", "-input-format", "lines", "-"},
+			"1\n2\n3\n2\n1\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := run(test.args, strings.NewReader(test.input), stdout, stderr)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, stdout.String()) {
+				t.Errorf("output mismatch (-want +got):\n%s", cmp.Diff(test.expected, stdout.String()))
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    int
+		args        []string
+	}{
+		{
+			"Success",
+			0,
+			[]string{"-season-length", "2"},
+		},
+		{
+			"Help",
+			0,
+			[]string{"-h"},
+		},
+		{
+			"Unknown flag",
+			2,
+			[]string{"-unknown"},
+		},
+		{
+			"Too many input files",
+			2,
+			[]string{"a.csv", "b.csv"},
+		},
+		{
+			"Invalid method",
+			2,
+			[]string{"-method", "exponential"},
+		},
+		{
+			"Invalid season length",
+			2,
+			[]string{"-season-length", "five"},
+		},
+		{
+			"Invalid input format",
+			2,
+			[]string{"-input-format", "xml"},
+		},
+		{
+			"Invalid output format",
+			2,
+			[]string{"-output-format", "xml"},
+		},
+		{
+			"Failure",
+			1,
+			[]string{"-season-length", "5"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := run(test.args, strings.NewReader("1\n2\n3\n2\n"), &bytes.Buffer{}, &bytes.Buffer{})
+			code := exitCode(err)
+			if test.expected != code {
+				t.Errorf("exit code mismatch, want %d, got %d, error: %v", test.expected, code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+)
+
+// Components is the decomposition of a smoothed series into its level, trend, seasonal and residual components at each
+// step, along with the model's state after the last step
+type Components struct {
+	// Level is the smoothed level at each step
+	Level []float64
+	// Trend is the smoothed trend at each step
+	Trend []float64
+	// Seasonal is the seasonal component for each step's position in the season, after smoothing that step, with
+	// additional season lengths it is the components for each season combined, added for the additive method or
+	// multiplied for the multiplicative method
+	Seasonal []float64
+	// Residual is the one-step-ahead error at each step, the difference between the observation and the prediction made
+	// for it from the previous step, 0 for the first step as it is used to initialise the level and NaN for missing
+	// observations
+	Residual []float64
+	// Final is a copy of the model's state after the last step, which can be used to forecast or take in new
+	// observations
+	Final Model
+}
+
+// Decompose fits the model to the series in the same way as Fit, returning the level, trend, seasonal and residual
+// components at each step of the series rather than the smoothed series. As with Fit, the model's state is left
+// unchanged if an error is returned.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the position in the season given by the model's Phase
+func (m *Model) Decompose(series []float64) (*Components, error) {
+	// Keep the state from before fitting to restore if fitting fails, so the model is still usable
+	previous := m.copy()
+	components, err := m.decompose(series)
+	if err != nil {
+		*m = previous
+		return nil, err
+	}
+	return components, nil
+}
+
+// decompose validates the model and the series, fits the model to it and ensures the components and the model's state
+// are finite
+func (m *Model) decompose(series []float64) (*Components, error) {
+	series, err := m.prepare(series)
+	if err != nil {
+		return nil, err
+	}
+
+	m.initialise(series)
+	components := &Components{
+		Level:    make([]float64, len(series)),
+		Trend:    make([]float64, len(series)),
+		Seasonal: make([]float64, len(series)),
+		Residual: make([]float64, len(series)),
+	}
+	components.Level[0] = m.Level
+	components.Trend[0] = m.Trend
+	components.Seasonal[0] = m.seasonal(0)
+	for i := 1; i < len(series); i++ {
+		components.Residual[i] = series[i] - m.oneStepForecast()
+		if missing(series[i]) {
+			components.Residual[i] = math.NaN()
+		}
+		m.update(series[i])
+		components.Level[i] = m.Level
+		components.Trend[i] = m.Trend
+		components.Seasonal[i] = m.seasonal(i)
+	}
+	err = components.validateFinite(series)
+	if err != nil {
+		return nil, err
+	}
+	err = m.validateFiniteState(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	components.Final = m.copy()
+	return components, nil
+}
+
+// validateFinite ensures every component is finite, other than the residuals of missing observations which are
+// intentionally NaN
+func (components *Components) validateFinite(series []float64) error {
+	for _, values := range [][]float64{components.Level, components.Trend, components.Seasonal} {
+		err := validateFinite(values)
+		if err != nil {
+			return err
+		}
+	}
+	for i, residual := range components.Residual {
+		if !missing(series[i]) && missing(residual) {
+			return fmt.Errorf("Result is not finite, residual %d is %f: %w", i, residual, ErrNonFiniteResult)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestModelDecompose(t *testing.T) {
+	equateReference := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description string
+		expected    *holtwinters.Components
+		expectedErr error
+		model       *holtwinters.Model
+		series      []float64
+	}{
+		{
+			"Fail, season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			holtwinters.NewModel(holtwinters.Additive, 1, 0.5, 0.3, 0.4),
+			[]float64{1, 2, 3, 2, 1},
+		},
+		{
+			"Success, additive",
+			&holtwinters.Components{
+				Level: []float64{1, 1.446, 1.6841, 1.817535, 1.8786222499999998, 1.9443725374999998, 1.830791800625, 1.85796266209375, 1.8957866935140624, 1.9268002551971093},
+				Trend: []float64{0.01200000000000001, 0.1422, 0.17096999999999998, 0.15970949999999998, 0.13012282499999994, 0.11081106374999995, 0.04349352356249999,
+					0.038596724934375, 0.038364916880156225, 0.03615951032102343},
+				Seasonal: []float64{-0.7799999999999999, 0.2936, 1.2583600000000001, 0.20498600000000006, -0.8194488999999998, -0.8057490149999998, 0.20384327975000002,
+					1.2518309351625, 0.20467692259437514, -0.8223894420788436},
+				Residual: []float64{0, 0.8679999999999999, 0.19179999999999975, -0.07507000000000019, -0.19724450000000004, -0.12874507499999988, -0.44878360125000016,
+					-0.03264532418749999, -0.0015453870281247895, -0.014702710394218776},
+				Final: holtwinters.Model{
+					Method:       holtwinters.Additive,
+					SeasonLength: 5,
+					Alpha:        0.5,
+					Beta:         0.3,
+					Gamma:        0.4,
+					Phi:          1,
+					Level:        1.9268002551971093,
+					Trend:        0.03615951032102343,
+					Seasonals:    [][]float64{{-0.8057490149999998, 0.20384327975000002, 1.2518309351625, 0.20467692259437514, -0.8223894420788436}},
+					Observations: 10,
+				},
+			},
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 5, 0.5, 0.3, 0.4),
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			components, err := test.model.Decompose(test.series)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, components, equateReference, cmpopts.IgnoreUnexported(holtwinters.Model{})) {
+				t.Errorf("components mismatch (-want +got):\n%s", cmp.Diff(test.expected, components, equateReference,
+					cmpopts.IgnoreUnexported(holtwinters.Model{})))
+			}
+		})
+	}
+}
+
+func TestModelDecomposeMatchesFit(t *testing.T) {
+	for _, method := range []holtwinters.Method{holtwinters.Additive, holtwinters.Multiplicative} {
+		t.Run(method.String(), func(t *testing.T) {
+			model := holtwinters.NewDampedModel(method, 12, 0.716, 0.029, 0.993, 0.95)
+			smoothed, err := model.Fit(modelTestSeries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			forecast, err := model.Forecast(12)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			components, err := holtwinters.NewDampedModel(method, 12, 0.716, 0.029, 0.993, 0.95).Decompose(modelTestSeries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Recombining the components should give the smoothed series
+			recombined := []float64{modelTestSeries[0]}
+			for i := 1; i < len(modelTestSeries); i++ {
+				level := components.Level[i] + 0.95*components.Trend[i]
+				if method == holtwinters.Multiplicative {
+					recombined = append(recombined, level*components.Seasonal[i])
+				} else {
+					recombined = append(recombined, level+components.Seasonal[i])
+				}
+			}
+			if !cmp.Equal(smoothed, recombined) {
+				t.Errorf("smoothed mismatch (-want +got):\n%s", cmp.Diff(smoothed, recombined))
+			}
+
+			finalForecast, err := components.Final.Forecast(12)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(forecast, finalForecast) {
+				t.Errorf("forecast mismatch (-want +got):\n%s", cmp.Diff(forecast, finalForecast))
+			}
+		})
+	}
+}
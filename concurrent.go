@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// runConcurrently calls run with each index from 0 up to n, spreading the calls over a number of goroutines, and waits
+// for them to finish. If the context is cancelled, cancelled is called instead for each index not yet started, with
+// the context's error. Each index is passed to exactly one of run and cancelled.
+// workers - Number of calls to make concurrently, if less than 1 runtime.GOMAXPROCS(0) is used
+func runConcurrently(ctx context.Context, n int, workers int, run func(index int), cancelled func(index int, err error)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				run(index)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			cancelled(i, ctx.Err())
+		}
+	}
+	close(indices)
+	wg.Wait()
+}
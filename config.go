@@ -0,0 +1,122 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+// Config configures a prediction made using Predict or PredictIntervals, fields added in future versions will default
+// to the current behaviour when left as their zero value
+type Config struct {
+	// Method is how the seasonal component is combined with the level and trend, defaults to Additive
+	Method Method
+	// SeasonLength is the length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the
+	// series
+	SeasonLength int
+	// Alpha is the exponential smoothing coefficient for level, must be between 0 and 1
+	Alpha float64
+	// Beta is the exponential smoothing coefficient for trend, must be between 0 and 1
+	Beta float64
+	// Gamma is the exponential smoothing coefficient for seasonality, must be between 0 and 1
+	Gamma float64
+	// Phi is the damping coefficient for trend, must be greater than 0 and at most 1, 1 applies no damping, 0 is
+	// treated as 1 so that the trend is not damped by default
+	Phi float64
+	// Phase is the position in the season of the first value of the series, for series that do not start at the
+	// beginning of a season, must be at least 0 and less than the season length
+	Phase int
+	// NonFinite is how non-finite observations, NaN and ±Inf, are handled, by default they are skipped as missing
+	// observations
+	NonFinite NonFinitePolicy
+	// PredictionLength is the number of predictions to make, set to 0 to make no predictions and only smooth, can't be
+	// negative
+	PredictionLength int
+}
+
+// Predict takes in a seasonal historical series of data and produces a prediction of what the data will be in the
+// future using triple exponential smoothing, configured by the config provided. Existing data will also be smoothed
+// alongside predictions. Returns the entire dataset with the predictions appended to the end.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the position in the season given by the config's Phase
+// config - The method, season length, smoothing coefficients and number of predictions to make
+func Predict(series []float64, config Config) ([]float64, error) {
+	return predict(series, config, config.phi())
+}
+
+// predict makes a prediction configured by the config provided, using the damping coefficient provided rather than the
+// config's, so that the prediction functions taking phi reject a phi of 0 rather than applying no damping
+func predict(series []float64, config Config, phi float64) ([]float64, error) {
+	model, series, err := config.model(series, phi)
+	if err != nil {
+		return nil, err
+	}
+	result := append(model.fit(series), model.forecast(config.PredictionLength)...)
+	err = validateFinite(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PredictIntervals takes in a seasonal historical series of data and produces predictions using the additive method,
+// configured by the config provided, with prediction intervals at the confidence level provided. Returns an Interval
+// for each step predicted, the smoothed existing data is not returned.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the position in the season given by the config's Phase
+// config - The season length, smoothing coefficients and number of predictions to make, the method must be Additive
+// confidence - Confidence level of the prediction intervals, must be greater than 0 and less than 1, for example 0.95
+// for 95% intervals
+func PredictIntervals(series []float64, config Config, confidence float64) ([]Interval, error) {
+	model, series, err := config.model(series, config.phi())
+	if err != nil {
+		return nil, err
+	}
+	err = validateConfidence(confidence)
+	if err != nil {
+		return nil, err
+	}
+	model.fit(series)
+	return model.ForecastIntervals(config.PredictionLength, confidence)
+}
+
+// phi returns the config's damping coefficient, with 0 treated as 1 so that the trend is not damped by default
+func (config Config) phi() float64 {
+	if config.Phi == 0 {
+		return 1
+	}
+	return config.Phi
+}
+
+// model creates an unfitted model from the config with the damping coefficient provided and validates it against the
+// series, returning the model and the series to fit it to. The season length, prediction length, smoothing
+// coefficients and series length are validated first, in the order the prediction functions have always validated
+// them, and then the rest of the config is validated by the model
+func (config Config) model(series []float64, phi float64) (*Model, []float64, error) {
+	seasonLength, err := resolveSeasonLength(series, config.SeasonLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = validateParams(series, seasonLength, config.Alpha, config.Beta, config.Gamma, config.PredictionLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	model := NewDampedModel(config.Method, seasonLength, config.Alpha, config.Beta, config.Gamma, phi)
+	model.Phase = config.Phase
+	model.NonFinite = config.NonFinite
+	series, err = model.prepare(series)
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, series, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestPredict(t *testing.T) {
+	mustPredict := func(result []float64, err error) []float64 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+	phased := holtwinters.NewModel(holtwinters.Multiplicative, 12, 0.5, 0.1, 0.3)
+	phased.Phase = 4
+	smoothed := mustPredict(phased.Fit(airlineTestSeries[4:]))
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		series      []float64
+		config      holtwinters.Config
+	}{
+		{
+			"Fail, unknown method",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "method", Value: 2, Range: "additive or multiplicative", Err: holtwinters.ErrMethod},
+			airlineTestSeries,
+			holtwinters.Config{Method: holtwinters.Method(2), SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3},
+		},
+		{
+			"Fail, negative prediction length",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -1, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength},
+			airlineTestSeries,
+			holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: -1},
+		},
+		{
+			"Fail, phi too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 1.5, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			airlineTestSeries,
+			holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, Phi: 1.5},
+		},
+		{
+			"Fail, rejected non-finite observation",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series value 0", Value: math.NaN(), Range: "finite", Err: holtwinters.ErrSeriesValue},
+			append([]float64{math.NaN()}, airlineTestSeries[1:]...),
+			holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, NonFinite: holtwinters.RejectNonFinite},
+		},
+		{
+			"Success, additive is the same as PredictAdditive",
+			mustPredict(holtwinters.PredictAdditive(airlineTestSeries, 12, 0.5, 0.1, 0.3, 12)),
+			nil,
+			airlineTestSeries,
+			holtwinters.Config{Method: holtwinters.Additive, SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: 12},
+		},
+		{
+			"Success, multiplicative is the same as PredictMultiplicative",
+			mustPredict(holtwinters.PredictMultiplicative(airlineTestSeries, 12, 0.5, 0.1, 0.3, 12)),
+			nil,
+			airlineTestSeries,
+			holtwinters.Config{Method: holtwinters.Multiplicative, SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: 12},
+		},
+		{
+			"Success, damped is the same as PredictAdditiveDamped",
+			mustPredict(holtwinters.PredictAdditiveDamped(airlineTestSeries, 12, 0.5, 0.1, 0.3, 0.9, 12)),
+			nil,
+			airlineTestSeries,
+			holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, Phi: 0.9, PredictionLength: 12},
+		},
+		{
+			"Success, phi of 0 applies no damping",
+			mustPredict(holtwinters.PredictAdditiveDamped(airlineTestSeries, 12, 0.5, 0.1, 0.3, 1, 12)),
+			nil,
+			airlineTestSeries,
+			holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: 12},
+		},
+		{
+			"Success, automatic season length",
+			mustPredict(holtwinters.PredictAdditive(airlineTestSeries, holtwinters.AutoSeasonLength, 0.5, 0.1, 0.3, 12)),
+			nil,
+			airlineTestSeries,
+			holtwinters.Config{SeasonLength: holtwinters.AutoSeasonLength, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: 12},
+		},
+		{
+			"Success, phase",
+			append(smoothed, mustPredict(phased.Forecast(6))...),
+			nil,
+			airlineTestSeries[4:],
+			holtwinters.Config{Method: holtwinters.Multiplicative, SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, Phase: 4, PredictionLength: 6},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.Predict(test.series, test.config)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
+
+func TestPredictIntervals(t *testing.T) {
+	expected, err := holtwinters.PredictAdditiveIntervals(airlineTestSeries, 12, 0.5, 0.1, 0.3, 12, 0.8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := holtwinters.Config{SeasonLength: 12, Alpha: 0.5, Beta: 0.1, Gamma: 0.3, PredictionLength: 12}
+	result, err := holtwinters.PredictIntervals(airlineTestSeries, config, 0.8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(expected, result) {
+		t.Errorf("intervals mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+	}
+
+	_, err = holtwinters.PredictIntervals(airlineTestSeries, config, 1.5)
+	if !errors.Is(err, holtwinters.ErrConfidence) {
+		t.Errorf("expected error to match ErrConfidence, got %v", err)
+	}
+
+	config.Method = holtwinters.Multiplicative
+	_, err = holtwinters.PredictIntervals(airlineTestSeries, config, 0.8)
+	expectedErr := &holtwinters.ParamError{Op: "prediction intervals", Name: "method", Value: holtwinters.Multiplicative, Range: "additive", Err: holtwinters.ErrMethod}
+	if !cmp.Equal(error(expectedErr), err, equateErrors) {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+	}
+}
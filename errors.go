@@ -0,0 +1,98 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidParameter is matched by every ParamError using errors.Is, regardless of which parameter was invalid
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// Sentinel errors for the parameters used when predicting, a ParamError for one of these parameters wraps the
+// matching sentinel so that it can be identified using errors.Is
+var (
+	// ErrSeasonLength is wrapped when the season length is too short
+	ErrSeasonLength = errors.New("invalid season length")
+	// ErrSeriesLength is wrapped when the series does not hold a full season of data
+	ErrSeriesLength = errors.New("invalid series length")
+	// ErrPredictionLength is wrapped when the prediction length is negative
+	ErrPredictionLength = errors.New("invalid prediction length")
+	// ErrAlpha is wrapped when the level smoothing coefficient is out of range
+	ErrAlpha = errors.New("invalid alpha")
+	// ErrBeta is wrapped when the trend smoothing coefficient is out of range
+	ErrBeta = errors.New("invalid beta")
+	// ErrGamma is wrapped when the seasonal smoothing coefficient is out of range
+	ErrGamma = errors.New("invalid gamma")
+	// ErrPhi is wrapped when the damping coefficient is out of range
+	ErrPhi = errors.New("invalid phi")
+	// ErrConfidence is wrapped when the confidence level of prediction intervals is out of range
+	ErrConfidence = errors.New("invalid confidence")
+	// ErrMethod is wrapped when the method is not additive or multiplicative
+	ErrMethod = errors.New("invalid method")
+	// ErrPhase is wrapped when the phase is outside of the season
+	ErrPhase = errors.New("invalid phase")
+	// ErrSeriesValue is wrapped when a value of the series is not finite and non-finite values are rejected, is not
+	// strictly positive when the method requires it, or there are too few finite values to skip or impute the rest
+	ErrSeriesValue = errors.New("invalid series value")
+	// ErrNonFinitePolicy is wrapped when the non-finite policy is not skip, reject or impute
+	ErrNonFinitePolicy = errors.New("invalid non-finite policy")
+	// ErrStep is wrapped when the step between timestamped observations is not greater than 0, or the observations are
+	// not evenly spaced by it
+	ErrStep = errors.New("invalid step")
+)
+
+// ErrNonFiniteResult is wrapped by the error returned when smoothing or forecasting produces a non-finite value, rather
+// than returning the non-finite value
+var ErrNonFiniteResult = errors.New("non-finite result")
+
+// ParamError is returned when a parameter is outside of the range of values allowed, it describes which parameter was
+// invalid, the value provided and the values allowed. Use errors.As to retrieve it, errors.Is with ErrInvalidParameter
+// to check for any invalid parameter, or errors.Is with one of the parameter sentinel errors, such as ErrAlpha, to
+// check for a specific parameter.
+type ParamError struct {
+	// Op is what the parameter was provided for, such as prediction or backtest
+	Op string
+	// Name is the name of the parameter, such as alpha or season length
+	Name string
+	// Value is the invalid value provided for the parameter
+	Value interface{}
+	// Range describes the values allowed for the parameter, such as "between 0 and 1"
+	Range string
+	// Err is the sentinel error for the parameter, such as ErrAlpha, nil for parameters without one
+	Err error
+}
+
+// Error describes the invalid parameter, the values allowed and the value provided
+func (e *ParamError) Error() string {
+	value := fmt.Sprintf("%v", e.Value)
+	if float, ok := e.Value.(float64); ok {
+		value = fmt.Sprintf("%f", float)
+	}
+	return fmt.Sprintf("Invalid parameter for %s; %s must be %s, is %s", e.Op, e.Name, e.Range, value)
+}
+
+// Unwrap returns the sentinel error for the parameter
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInvalidParameter, which every ParamError matches
+func (e *ParamError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
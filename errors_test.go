@@ -0,0 +1,213 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+// equateErrors compares the errors returned in the table driven tests. A ParamError is compared by its fields, with the
+// value compared by type as well as value and the sentinel by identity, so that the wrong sentinel or parameter is
+// caught rather than only a message that reads the same. Any other error is compared by its message and the sentinel
+// error at the end of its chain of wrapped errors, such as ErrNonFiniteResult.
+var equateErrors = cmp.Comparer(func(x, y error) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	xParam, xIsParam := x.(*holtwinters.ParamError)
+	yParam, yIsParam := y.(*holtwinters.ParamError)
+	if xIsParam || yIsParam {
+		return xIsParam && yIsParam && xParam.Op == yParam.Op && xParam.Name == yParam.Name &&
+			xParam.Range == yParam.Range && xParam.Err == yParam.Err &&
+			cmp.Equal(xParam.Value, yParam.Value, cmpopts.EquateNaNs())
+	}
+	return x.Error() == y.Error() && wrappedSentinel(x) == wrappedSentinel(y)
+})
+
+// wrappedSentinel returns the error at the end of the chain of errors wrapped by the error provided, nil if it does not
+// wrap an error
+func wrappedSentinel(err error) error {
+	var sentinel error
+	for wrapped := errors.Unwrap(err); wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		sentinel = wrapped
+	}
+	return sentinel
+}
+
+func TestParamError(t *testing.T) {
+	equateSentinel := cmp.Comparer(func(x, y error) bool {
+		return x == y
+	})
+
+	var tests = []struct {
+		description string
+		expected    *holtwinters.ParamError
+		sentinel    error
+		err         error
+	}{
+		{
+			"Season length",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			holtwinters.ErrSeasonLength,
+			func() error {
+				_, err := holtwinters.PredictAdditive([]float64{1, 2, 3}, 1, 0.5, 0.5, 0.5, 1)
+				return err
+			}(),
+		},
+		{
+			"Series length",
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 4", Err: holtwinters.ErrSeriesLength},
+			holtwinters.ErrSeriesLength,
+			func() error {
+				_, err := holtwinters.PredictMultiplicative([]float64{1, 2, 3}, 4, 0.5, 0.5, 0.5, 1)
+				return err
+			}(),
+		},
+		{
+			"Alpha",
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.5, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			holtwinters.ErrAlpha,
+			func() error {
+				_, err := holtwinters.PredictAdditive([]float64{1, 2, 3, 4}, 2, 1.5, 0.5, 0.5, 1)
+				return err
+			}(),
+		},
+		{
+			"Phi",
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 1.2, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			holtwinters.ErrPhi,
+			func() error {
+				_, err := holtwinters.PredictAdditiveDamped([]float64{1, 2, 3, 4}, 2, 0.5, 0.5, 0.5, 1.2, 1)
+				return err
+			}(),
+		},
+		{
+			"Phase",
+			&holtwinters.ParamError{Op: "prediction", Name: "phase", Value: 2, Range: "at least 0 and less than the season length 2", Err: holtwinters.ErrPhase},
+			holtwinters.ErrPhase,
+			func() error {
+				model := holtwinters.NewModel(holtwinters.Additive, 2, 0.5, 0.5, 0.5)
+				model.Phase = 2
+				_, err := model.Fit([]float64{1, 2, 3, 4})
+				return err
+			}(),
+		},
+		{
+			"Season length not detected",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: "AutoSeasonLength", Range: "provided, as no season length could be detected from the series", Err: holtwinters.ErrSeasonLength},
+			holtwinters.ErrSeasonLength,
+			func() error {
+				_, err := holtwinters.PredictAdditive([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, holtwinters.AutoSeasonLength, 0.5, 0.5, 0.5, 1)
+				return err
+			}(),
+		},
+		{
+			"Gamma for each season length",
+			&holtwinters.ParamError{Op: "prediction", Name: "number of gammas", Value: 1, Range: "the number of season lengths 2", Err: holtwinters.ErrGamma},
+			holtwinters.ErrGamma,
+			func() error {
+				_, err := holtwinters.PredictAdditiveMultiSeasonal([]float64{1, 2, 3, 4, 5, 6}, []int{2, 3}, 0.5, 0.5, []float64{0.5}, 1)
+				return err
+			}(),
+		},
+		{
+			"Box-Cox series value",
+			&holtwinters.ParamError{Op: "Box-Cox transformation", Name: "series value 1", Value: -2.0, Range: "strictly positive", Err: holtwinters.ErrSeriesValue},
+			holtwinters.ErrSeriesValue,
+			func() error {
+				_, err := holtwinters.BoxCoxTransform([]float64{1, -2, 3}, 0.5)
+				return err
+			}(),
+		},
+		{
+			"Non-finite policy",
+			&holtwinters.ParamError{Op: "prediction", Name: "non-finite policy", Value: -1, Range: "skip, reject or impute", Err: holtwinters.ErrNonFinitePolicy},
+			holtwinters.ErrNonFinitePolicy,
+			func() error {
+				_, err := holtwinters.Predict([]float64{1, 2, 3, 4}, holtwinters.Config{SeasonLength: 2, NonFinite: -1})
+				return err
+			}(),
+		},
+		{
+			"Confidence, wrapped",
+			&holtwinters.ParamError{Op: "prediction", Name: "confidence", Value: 1.0, Range: "greater than 0 and less than 1", Err: holtwinters.ErrConfidence},
+			holtwinters.ErrConfidence,
+			func() error {
+				_, err := holtwinters.PredictAdditiveIntervals([]float64{1, 2, 3, 4}, 2, 0.5, 0.5, 0.5, 1, 1)
+				return fmt.Errorf("failed to predict: %w", err)
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if !errors.Is(test.err, holtwinters.ErrInvalidParameter) {
+				t.Errorf("expected error to match ErrInvalidParameter, got %v", test.err)
+			}
+			if !errors.Is(test.err, test.sentinel) {
+				t.Errorf("expected error to match %v, got %v", test.sentinel, test.err)
+			}
+			var paramErr *holtwinters.ParamError
+			if !errors.As(test.err, &paramErr) {
+				t.Fatalf("expected a ParamError, got %v", test.err)
+			}
+			if !cmp.Equal(*test.expected, *paramErr, equateSentinel) {
+				t.Errorf("param error mismatch (-want +got):\n%s", cmp.Diff(*test.expected, *paramErr, equateSentinel))
+			}
+		})
+	}
+}
+
+func TestParamErrorError(t *testing.T) {
+	var tests = []struct {
+		description string
+		expected    string
+		err         *holtwinters.ParamError
+	}{
+		{
+			"Integer value",
+			"Invalid parameter for backtest; step must be at least 1, is 0",
+			&holtwinters.ParamError{Op: "backtest", Name: "step", Value: 0, Range: "at least 1"},
+		},
+		{
+			"Float value",
+			"Invalid parameter for prediction; beta must be between 0 and 1, is -0.500000",
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: -0.5, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if test.expected != test.err.Error() {
+				t.Errorf("message mismatch, want %q, got %q", test.expected, test.err.Error())
+			}
+		})
+	}
+}
+
+func TestParamErrorIsNotOtherSentinel(t *testing.T) {
+	_, err := holtwinters.PredictAdditive([]float64{1, 2, 3, 4}, 2, 1.5, 0.5, 0.5, 1)
+	for _, sentinel := range []error{holtwinters.ErrBeta, holtwinters.ErrGamma, holtwinters.ErrSeasonLength} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("expected error not to match %v, got %v", sentinel, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// estimateMaxIterations is the maximum number of Nelder-Mead iterations used when estimating parameters
+	estimateMaxIterations = 1000
+	// estimateTolerance is the relative difference between the best and worst points of the simplex below which the
+	// search is considered to have converged
+	estimateTolerance = 1e-10
+)
+
+// Estimate is the result of estimating the smoothing coefficients for a series
+type Estimate struct {
+	// Alpha is the chosen exponential smoothing coefficient for level
+	Alpha float64
+	// Beta is the chosen exponential smoothing coefficient for trend
+	Beta float64
+	// Gamma is the chosen exponential smoothing coefficient for seasonality
+	Gamma float64
+	// Loss is the sum of squared one-step-ahead errors achieved by the chosen coefficients
+	Loss float64
+	// Iterations is the number of iterations the search took
+	Iterations int
+	// Converged is true if the search converged before reaching the maximum number of iterations
+	Converged bool
+}
+
+// EstimateParameters estimates the alpha, beta and gamma smoothing coefficients for a series, using the Nelder-Mead
+// method to search between 0 and 1 for each coefficient for those that minimise the sum of squared one-step-ahead
+// errors when smoothing the series. If no coefficients give a finite loss, such as for a multiplicative model of a
+// series with a season averaging 0, an error wrapping ErrNonFiniteResult is returned.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// method - The method used to combine the seasonal component with the level and trend
+func EstimateParameters(series []float64, seasonLength int, method Method) (*Estimate, error) {
+	seasonLength, err := resolveSeasonLength(series, seasonLength)
+	if err != nil {
+		return nil, err
+	}
+	series, err = NewModel(method, seasonLength, 0, 0, 0).prepare(series)
+	if err != nil {
+		return nil, err
+	}
+
+	loss := func(params []float64) float64 {
+		model := NewModel(method, seasonLength, params[0], params[1], params[2])
+		return model.fitSSE(series)
+	}
+	result := nelderMead(loss, []float64{0.3, 0.1, 0.1}, []float64{0, 0, 0}, []float64{1, 1, 1})
+	if math.IsInf(result.value, 0) || math.IsNaN(result.value) {
+		return nil, fmt.Errorf("Loss is not finite for any coefficients tried, the series is unsuitable for the %s method: %w", method, ErrNonFiniteResult)
+	}
+
+	return &Estimate{
+		Alpha:      result.x[0],
+		Beta:       result.x[1],
+		Gamma:      result.x[2],
+		Loss:       result.value,
+		Iterations: result.iterations,
+		Converged:  result.converged,
+	}, nil
+}
+
+// nelderMeadResult is the best point found by a Nelder-Mead search
+type nelderMeadResult struct {
+	x          []float64
+	value      float64
+	iterations int
+	converged  bool
+}
+
+// nelderMead minimises the function provided using the Nelder-Mead simplex method, starting from the point provided and
+// keeping every point within the lower and upper bounds by clamping. Non finite function values are treated as
+// infinitely bad, so regions where the function is undefined are avoided.
+func nelderMead(f func([]float64) float64, start []float64, lower []float64, upper []float64) nelderMeadResult {
+	n := len(start)
+
+	clamp := func(x []float64) []float64 {
+		for i := range x {
+			x[i] = math.Max(lower[i], math.Min(upper[i], x[i]))
+		}
+		return x
+	}
+	evaluate := func(x []float64) float64 {
+		value := f(x)
+		if math.IsNaN(value) {
+			return math.Inf(1)
+		}
+		return value
+	}
+	// combine returns the point a + t(b - a), clamped to the bounds
+	combine := func(a []float64, b []float64, t float64) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = a[i] + t*(b[i]-a[i])
+		}
+		return clamp(x)
+	}
+
+	// Initial simplex, the start point plus a step along each axis, stepping back from the upper bound if needed
+	points := make([][]float64, n+1)
+	values := make([]float64, n+1)
+	points[0] = clamp(append([]float64{}, start...))
+	for i := 0; i < n; i++ {
+		point := append([]float64{}, points[0]...)
+		step := 0.1 * (upper[i] - lower[i])
+		if point[i]+step > upper[i] {
+			step = -step
+		}
+		point[i] += step
+		points[i+1] = clamp(point)
+	}
+	for i, point := range points {
+		values[i] = evaluate(point)
+	}
+
+	order := make([]int, n+1)
+	iterations := 0
+	converged := false
+	for ; iterations < estimateMaxIterations; iterations++ {
+		// Sort so the best point is first and the worst last
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return values[order[a]] < values[order[b]]
+		})
+		sortedPoints := make([][]float64, n+1)
+		sortedValues := make([]float64, n+1)
+		for i, j := range order {
+			sortedPoints[i] = points[j]
+			sortedValues[i] = values[j]
+		}
+		points, values = sortedPoints, sortedValues
+
+		best, worst := values[0], values[n]
+		if !math.IsInf(best, 1) && math.Abs(worst-best) <= estimateTolerance*(math.Abs(best)+estimateTolerance) {
+			converged = true
+			break
+		}
+
+		// Centroid of every point except the worst
+		centroid := make([]float64, n)
+		for _, point := range points[:n] {
+			for i := range centroid {
+				centroid[i] += point[i] / float64(n)
+			}
+		}
+
+		reflected := combine(centroid, points[n], -1)
+		reflectedValue := evaluate(reflected)
+		switch {
+		case reflectedValue < values[0]:
+			expanded := combine(centroid, points[n], -2)
+			expandedValue := evaluate(expanded)
+			if expandedValue < reflectedValue {
+				points[n], values[n] = expanded, expandedValue
+			} else {
+				points[n], values[n] = reflected, reflectedValue
+			}
+		case reflectedValue < values[n-1]:
+			points[n], values[n] = reflected, reflectedValue
+		default:
+			contracted := combine(centroid, points[n], 0.5)
+			contractedValue := evaluate(contracted)
+			if contractedValue < values[n] {
+				points[n], values[n] = contracted, contractedValue
+				continue
+			}
+			// Shrink every point towards the best
+			for i := 1; i <= n; i++ {
+				points[i] = combine(points[0], points[i], 0.5)
+				values[i] = evaluate(points[i])
+			}
+		}
+	}
+
+	bestIndex := 0
+	for i := range values {
+		if values[i] < values[bestIndex] {
+			bestIndex = i
+		}
+	}
+	return nelderMeadResult{
+		x:          points[bestIndex],
+		value:      values[bestIndex],
+		iterations: iterations,
+		converged:  converged,
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestEstimateParameters(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-6)
+
+	var tests = []struct {
+		description  string
+		expected     *holtwinters.Estimate
+		expectedErr  error
+		series       []float64
+		seasonLength int
+		method       holtwinters.Method
+		// gridLoss is the lowest loss found by searching every combination of alpha, beta and gamma in steps of 0.05,
+		// the estimate should always be at least as good
+		gridLoss float64
+	}{
+		{
+			"Fail, season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			[]float64{1, 2, 3, 2, 1},
+			1,
+			holtwinters.Additive,
+			0,
+		},
+		{
+			"Fail, data provided less than full season",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
+			[]float64{1, 2, 3},
+			5,
+			holtwinters.Additive,
+			0,
+		},
+		{
+			"Fail, unknown method",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "method", Value: 3, Range: "additive or multiplicative", Err: holtwinters.ErrMethod},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			holtwinters.Method(3),
+			0,
+		},
+		{
+			"Success, additive",
+			&holtwinters.Estimate{
+				Alpha:      0.6366195894447133,
+				Beta:       0.04498592807892766,
+				Gamma:      0,
+				Loss:       553.8511092406227,
+				Iterations: 61,
+				Converged:  true,
+			},
+			nil,
+			modelTestSeries,
+			12,
+			holtwinters.Additive,
+			554.2547523972644,
+		},
+		{
+			"Success, multiplicative",
+			&holtwinters.Estimate{
+				Alpha:      0.4228267151650063,
+				Beta:       0.054206584977141176,
+				Gamma:      0,
+				Loss:       611.127003084704,
+				Iterations: 59,
+				Converged:  true,
+			},
+			nil,
+			modelTestSeries,
+			12,
+			holtwinters.Multiplicative,
+			611.7974950548178,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			estimate, err := holtwinters.EstimateParameters(test.series, test.seasonLength, test.method)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, estimate, equateApprox) {
+				t.Errorf("estimate mismatch (-want +got):\n%s", cmp.Diff(test.expected, estimate, equateApprox))
+			}
+
+			if estimate != nil && estimate.Loss > test.gridLoss {
+				t.Errorf("estimate loss %f worse than grid search loss %f", estimate.Loss, test.gridLoss)
+			}
+		})
+	}
+}
@@ -0,0 +1,636 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// etsMinSmoothing is the smallest value alpha, beta and gamma can take when fitting an ETS model
+	etsMinSmoothing = 1e-4
+	// etsMinPhi is the smallest value phi can take when fitting a damped ETS model
+	etsMinPhi = 0.8
+	// etsMaxPhi is the largest value phi can take when fitting a damped ETS model
+	etsMaxPhi = 0.98
+	// etsMinVariance is the smallest variance of the errors used for the likelihood, relative to the mean square of the
+	// series for additive errors, so that a series fitted perfectly has a finite likelihood
+	etsMinVariance = 1e-12
+)
+
+// ErrorType is the way the error is combined with the forecast in an ETS model
+type ErrorType int
+
+const (
+	// AdditiveError is an error added to the forecast
+	AdditiveError ErrorType = iota
+	// MultiplicativeError is an error relative to the forecast, only supported for strictly positive series
+	MultiplicativeError
+)
+
+// String returns the short name of the error type used in the ETS taxonomy
+func (errorType ErrorType) String() string {
+	switch errorType {
+	case AdditiveError:
+		return "A"
+	case MultiplicativeError:
+		return "M"
+	}
+	return fmt.Sprintf("ErrorType(%d)", int(errorType))
+}
+
+// TrendType is the way the trend is combined with the level in an ETS model
+type TrendType int
+
+const (
+	// NoTrend is a model without a trend
+	NoTrend TrendType = iota
+	// AdditiveTrend is a trend added to the level
+	AdditiveTrend
+	// AdditiveDampedTrend is a trend added to the level, damped by phi
+	AdditiveDampedTrend
+	// MultiplicativeTrend is a trend the level is multiplied by, only supported for strictly positive series
+	MultiplicativeTrend
+	// MultiplicativeDampedTrend is a trend the level is multiplied by, damped by phi, only supported for strictly
+	// positive series
+	MultiplicativeDampedTrend
+)
+
+// String returns the short name of the trend type used in the ETS taxonomy
+func (trendType TrendType) String() string {
+	switch trendType {
+	case NoTrend:
+		return "N"
+	case AdditiveTrend:
+		return "A"
+	case AdditiveDampedTrend:
+		return "Ad"
+	case MultiplicativeTrend:
+		return "M"
+	case MultiplicativeDampedTrend:
+		return "Md"
+	}
+	return fmt.Sprintf("TrendType(%d)", int(trendType))
+}
+
+// SeasonType is the way the seasonal component is combined with the level and trend in an ETS model
+type SeasonType int
+
+const (
+	// NoSeason is a model without a seasonal component
+	NoSeason SeasonType = iota
+	// AdditiveSeason is a seasonal component added to the level and trend
+	AdditiveSeason
+	// MultiplicativeSeason is a seasonal component the level and trend are multiplied by, only supported for strictly
+	// positive series
+	MultiplicativeSeason
+)
+
+// String returns the short name of the season type used in the ETS taxonomy
+func (seasonType SeasonType) String() string {
+	switch seasonType {
+	case NoSeason:
+		return "N"
+	case AdditiveSeason:
+		return "A"
+	case MultiplicativeSeason:
+		return "M"
+	}
+	return fmt.Sprintf("SeasonType(%d)", int(seasonType))
+}
+
+// ETSSpec is the combination of error, trend and season types of an ETS model
+type ETSSpec struct {
+	// Error is the error type
+	Error ErrorType
+	// Trend is the trend type
+	Trend TrendType
+	// Season is the season type
+	Season SeasonType
+}
+
+// String returns the name of the model in the ETS taxonomy, for example ETS(M,Ad,M)
+func (spec ETSSpec) String() string {
+	return fmt.Sprintf("ETS(%s,%s,%s)", spec.Error, spec.Trend, spec.Season)
+}
+
+// multiplicative returns true if any component of the model is multiplicative, requiring a strictly positive series
+func (spec ETSSpec) multiplicative() bool {
+	return spec.Error == MultiplicativeError || spec.Trend == MultiplicativeTrend || spec.Trend == MultiplicativeDampedTrend ||
+		spec.Season == MultiplicativeSeason
+}
+
+// damped returns true if the model's trend is damped
+func (spec ETSSpec) damped() bool {
+	return spec.Trend == AdditiveDampedTrend || spec.Trend == MultiplicativeDampedTrend
+}
+
+// validate ensures the types of the model are known
+func (spec ETSSpec) validate() error {
+	if spec.Error != AdditiveError && spec.Error != MultiplicativeError {
+		return &ParamError{Op: "prediction", Name: "error type", Value: int(spec.Error), Range: "additive or multiplicative"}
+	}
+	if spec.Trend < NoTrend || spec.Trend > MultiplicativeDampedTrend {
+		return &ParamError{Op: "prediction", Name: "trend type", Value: int(spec.Trend), Range: "none, additive, additive damped, multiplicative or multiplicative damped"}
+	}
+	if spec.Season < NoSeason || spec.Season > MultiplicativeSeason {
+		return &ParamError{Op: "prediction", Name: "season type", Value: int(spec.Season), Range: "none, additive or multiplicative"}
+	}
+	return nil
+}
+
+// ETSModel is an exponential smoothing state space model fitted by maximum likelihood, following the taxonomy of
+// Hyndman et al., Forecasting with Exponential Smoothing. The smoothing coefficients are those of the error correction
+// form, so the trend is updated by Beta times the error rather than the change in level, and they are kept within
+// the usual region of 0 < Beta < Alpha and 0 < Gamma < 1 - Alpha.
+type ETSModel struct {
+	// Spec is the error, trend and season types of the model
+	Spec ETSSpec
+	// SeasonLength is the length of the data's seasons, 1 for a model with no seasonal component
+	SeasonLength int
+	// Alpha is the smoothing coefficient for level
+	Alpha float64
+	// Beta is the smoothing coefficient for trend, 0 for a model with no trend
+	Beta float64
+	// Gamma is the smoothing coefficient for seasonality, 0 for a model with no seasonal component
+	Gamma float64
+	// Phi is the damping coefficient for trend, 1 for a model without a damped trend
+	Phi float64
+	// Level is the level after the last observation
+	Level float64
+	// Trend is the trend after the last observation, a difference for an additive trend and a ratio for a
+	// multiplicative trend, 0 for a model with no trend
+	Trend float64
+	// Seasonals are the seasonal components after the last observation, one for each position in the season, nil for
+	// a model with no seasonal component
+	Seasonals []float64
+	// Observations is the number of observations the model was fitted to
+	Observations int
+	// Fitted are the one-step-ahead forecasts for each observation
+	Fitted []float64
+	// Variance is the maximum likelihood estimate of the variance of the errors
+	Variance float64
+	// Parameters is the number of parameters estimated from the series, the smoothing coefficients, the initial states
+	// and the variance, used to calculate the information criteria
+	Parameters int
+	// LogLikelihood is the Gaussian log-likelihood of the series given the model
+	LogLikelihood float64
+	// AIC is the Akaike information criterion
+	AIC float64
+	// AICc is the Akaike information criterion corrected for small samples, +Inf if there are too few observations for
+	// the number of parameters
+	AICc float64
+	// BIC is the Bayesian information criterion
+	BIC float64
+}
+
+// etsState is the level, trend and seasonal components of an ETS model
+type etsState struct {
+	level     float64
+	trend     float64
+	seasonals []float64
+}
+
+// etsParams are the smoothing and damping coefficients of an ETS model
+type etsParams struct {
+	alpha float64
+	beta  float64
+	gamma float64
+	phi   float64
+}
+
+// FitETS fits an ETS model to a series, choosing the smoothing and damping coefficients that maximise the likelihood
+// of the series using the Nelder-Mead method. The initial states are estimated from the start of the series, using
+// the average of each position in the season for the seasonal components and a least squares linear fit of the
+// seasonally adjusted series for the level and trend. Missing (NaN) observations are skipped, the states are moved
+// forward as if the observation had been the same as the forecast for it.
+// series - Historical data, must be at least a full season and two observations, the first value should be at the
+// start of a season, must be strictly positive if any component of the model is multiplicative
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the
+// series, ignored for models with no seasonal component
+// spec - The error, trend and season types of the model
+func FitETS(series []float64, seasonLength int, spec ETSSpec) (*ETSModel, error) {
+	err := spec.validate()
+	if err != nil {
+		return nil, err
+	}
+	if spec.Season == NoSeason {
+		seasonLength = 1
+	} else {
+		seasonLength, err = resolveSeasonLength(series, seasonLength)
+		if err != nil {
+			return nil, err
+		}
+		if seasonLength <= 1 {
+			return nil, &ParamError{Op: "prediction", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
+		}
+	}
+	err = validateETSSeries(series, seasonLength, spec)
+	if err != nil {
+		return nil, err
+	}
+	return fitETS(series, seasonLength, spec)
+}
+
+// SelectETS fits every admissible ETS model to a series using FitETS and returns the model with the lowest AICc, in
+// the same way as R's ets function. Models with an additive error and a multiplicative trend or seasonal component
+// are not considered as they are numerically unstable, and models with any multiplicative component are only
+// considered if the series is strictly positive.
+// series - Historical data, must be at least a full season and two observations, the first value should be at the
+// start of a season
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the
+// series, 1 to only consider models with no seasonal component
+func SelectETS(series []float64, seasonLength int) (*ETSModel, error) {
+	if seasonLength != 1 {
+		var err error
+		seasonLength, err = resolveSeasonLength(series, seasonLength)
+		if err != nil {
+			return nil, err
+		}
+		if seasonLength <= 1 {
+			return nil, &ParamError{Op: "prediction", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
+		}
+	}
+	err := validateETSSeries(series, seasonLength, ETSSpec{})
+	if err != nil {
+		return nil, err
+	}
+	positive := validatePositiveSeries(series) == nil
+
+	seasons := []SeasonType{NoSeason}
+	if seasonLength > 1 {
+		seasons = append(seasons, AdditiveSeason, MultiplicativeSeason)
+	}
+	var best *ETSModel
+	for _, errorType := range []ErrorType{AdditiveError, MultiplicativeError} {
+		for _, trendType := range []TrendType{NoTrend, AdditiveTrend, AdditiveDampedTrend, MultiplicativeTrend, MultiplicativeDampedTrend} {
+			for _, seasonType := range seasons {
+				spec := ETSSpec{Error: errorType, Trend: trendType, Season: seasonType}
+				if spec.multiplicative() && !positive {
+					continue
+				}
+				if errorType == AdditiveError && (trendType == MultiplicativeTrend || trendType == MultiplicativeDampedTrend || seasonType == MultiplicativeSeason) {
+					continue
+				}
+				modelSeasonLength := seasonLength
+				if seasonType == NoSeason {
+					modelSeasonLength = 1
+				}
+				model, err := fitETS(series, modelSeasonLength, spec)
+				if err != nil || math.IsNaN(model.AICc) || math.IsInf(model.AICc, 0) {
+					continue
+				}
+				if best == nil || model.AICc < best.AICc {
+					best = model
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, errors.New("Unable to fit any ETS model to the series")
+	}
+	return best, nil
+}
+
+// Forecast makes predictions for the steps following the last observation the model was fitted to.
+// predictionLength - Number of predictions to make, can't be negative
+func (m *ETSModel) Forecast(predictionLength int) ([]float64, error) {
+	if m.Observations == 0 {
+		return nil, errors.New("Model must be fitted before forecasting")
+	}
+	if predictionLength < 0 {
+		return nil, &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
+	}
+	result := make([]float64, predictionLength)
+	for step := 1; step <= predictionLength; step++ {
+		var forecast float64
+		switch m.Spec.Trend {
+		case NoTrend:
+			forecast = m.Level
+		case AdditiveTrend, AdditiveDampedTrend:
+			forecast = m.Level + dampedTrendMultiplier(m.Phi, step)*m.Trend
+		case MultiplicativeTrend, MultiplicativeDampedTrend:
+			forecast = m.Level * math.Pow(m.Trend, dampedTrendMultiplier(m.Phi, step))
+		}
+		switch m.Spec.Season {
+		case AdditiveSeason:
+			forecast += m.Seasonals[(m.Observations+step-1)%m.SeasonLength]
+		case MultiplicativeSeason:
+			forecast *= m.Seasonals[(m.Observations+step-1)%m.SeasonLength]
+		}
+		result[step-1] = forecast
+	}
+	return result, nil
+}
+
+// fitETS fits an ETS model to a series by maximum likelihood, assumes the spec, season length and series have been
+// validated
+func fitETS(series []float64, seasonLength int, spec ETSSpec) (*ETSModel, error) {
+	initial, err := initialETSState(series, seasonLength, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// The coefficients are searched for between 0 and 1 and then mapped to the usual region, beta and gamma as a
+	// proportion of their upper bounds
+	dimensions := 1
+	if spec.Trend != NoTrend {
+		dimensions++
+	}
+	if spec.Season != NoSeason {
+		dimensions++
+	}
+	if spec.damped() {
+		dimensions++
+	}
+	toParams := func(x []float64) etsParams {
+		params := etsParams{
+			alpha: etsMinSmoothing + x[0]*(1-2*etsMinSmoothing),
+			phi:   1,
+		}
+		i := 1
+		if spec.Trend != NoTrend {
+			params.beta = etsMinSmoothing + x[i]*(params.alpha-2*etsMinSmoothing)
+			i++
+		}
+		if spec.Season != NoSeason {
+			params.gamma = etsMinSmoothing + x[i]*(1-params.alpha-2*etsMinSmoothing)
+			i++
+		}
+		if spec.damped() {
+			params.phi = etsMinPhi + x[i]*(etsMaxPhi-etsMinPhi)
+		}
+		return params
+	}
+	start := []float64{0.3, 0.1, 0.1, 0.5}[:dimensions]
+	lower := make([]float64, dimensions)
+	upper := make([]float64, dimensions)
+	for i := range upper {
+		upper[i] = 1
+	}
+	loss := func(x []float64) float64 {
+		_, _, _, objective := runETS(series, seasonLength, spec, toParams(x), initial)
+		return objective
+	}
+	result := nelderMead(loss, start, lower, upper)
+	if math.IsInf(result.value, 1) || math.IsNaN(result.value) {
+		return nil, fmt.Errorf("Unable to fit %s to the series", spec)
+	}
+
+	params := toParams(result.x)
+	final, fitted, sse, _ := runETS(series, seasonLength, spec, params, initial)
+	n := 0
+	sumLogForecast := float64(0)
+	for i, val := range series {
+		if missing(val) {
+			continue
+		}
+		n++
+		if spec.Error == MultiplicativeError {
+			sumLogForecast += math.Log(math.Abs(fitted[i]))
+		}
+	}
+
+	// The initial level and trend, and the seasonal components except one as they are normalised, are estimated from
+	// the series, as well as the smoothing coefficients and the variance
+	states := 1
+	if spec.Trend != NoTrend {
+		states++
+	}
+	if spec.Season != NoSeason {
+		states += seasonLength - 1
+	}
+	k := dimensions + states + 1
+	variance := sse / float64(n)
+	logLikelihood := -0.5*float64(n)*(math.Log(2*math.Pi*variance)+1) - sumLogForecast
+	aic := -2*logLikelihood + 2*float64(k)
+	aicc := math.Inf(1)
+	if n-k-1 > 0 {
+		aicc = aic + 2*float64(k*(k+1))/float64(n-k-1)
+	}
+	return &ETSModel{
+		Spec:          spec,
+		SeasonLength:  seasonLength,
+		Alpha:         params.alpha,
+		Beta:          params.beta,
+		Gamma:         params.gamma,
+		Phi:           params.phi,
+		Level:         final.level,
+		Trend:         final.trend,
+		Seasonals:     final.seasonals,
+		Observations:  len(series),
+		Fitted:        fitted,
+		Variance:      variance,
+		Parameters:    k,
+		LogLikelihood: logLikelihood,
+		AIC:           aic,
+		AICc:          aicc,
+		BIC:           -2*logLikelihood + math.Log(float64(n))*float64(k),
+	}, nil
+}
+
+// runETS applies the ETS recurrences to the series from the initial state, returning the final state, the one-step-ahead
+// forecasts, the sum of squared errors and the objective minimised when fitting, which is -2 times the log-likelihood
+// with the constant terms removed. The sum of squared errors is at least etsMinVariance for each observation, scaled by
+// the mean square of the series for additive errors. The objective is +Inf if the recurrences break down, such as a
+// forecast of 0 with a multiplicative error
+func runETS(series []float64, seasonLength int, spec ETSSpec, params etsParams, initial etsState) (etsState, []float64, float64, float64) {
+	state := etsState{
+		level: initial.level,
+		trend: initial.trend,
+	}
+	if initial.seasonals != nil {
+		state.seasonals = append([]float64{}, initial.seasonals...)
+	}
+	fitted := make([]float64, len(series))
+	n := 0
+	sse := float64(0)
+	sumSquares := float64(0)
+	sumLogForecast := float64(0)
+	for i, val := range series {
+		slot := i % seasonLength
+
+		// Forecast for this step from the previous state, phiTrend is the damped trend applied to the level
+		var levelTrend, phiTrend float64
+		switch spec.Trend {
+		case NoTrend:
+			levelTrend = state.level
+		case AdditiveTrend, AdditiveDampedTrend:
+			phiTrend = params.phi * state.trend
+			levelTrend = state.level + phiTrend
+		case MultiplicativeTrend, MultiplicativeDampedTrend:
+			phiTrend = math.Pow(state.trend, params.phi)
+			levelTrend = state.level * phiTrend
+		}
+		forecast := levelTrend
+		switch spec.Season {
+		case AdditiveSeason:
+			forecast += state.seasonals[slot]
+		case MultiplicativeSeason:
+			forecast *= state.seasonals[slot]
+		}
+		fitted[i] = forecast
+		if math.IsNaN(forecast) || math.IsInf(forecast, 0) || (spec.Error == MultiplicativeError && forecast == 0) {
+			return state, fitted, math.Inf(1), math.Inf(1)
+		}
+
+		if missing(val) {
+			val = forecast
+		} else {
+			n++
+			sumSquares += val * val
+			if spec.Error == MultiplicativeError {
+				relative := (val - forecast) / forecast
+				sse += relative * relative
+				sumLogForecast += math.Log(math.Abs(forecast))
+			} else {
+				sse += (val - forecast) * (val - forecast)
+			}
+		}
+
+		// Update the states, in the form of Hyndman et al. which is equivalent to the error correction form for both
+		// additive and multiplicative errors
+		adjusted := val
+		switch spec.Season {
+		case AdditiveSeason:
+			adjusted = val - state.seasonals[slot]
+		case MultiplicativeSeason:
+			adjusted = val / state.seasonals[slot]
+		}
+		lastLevel := state.level
+		state.level = levelTrend + params.alpha*(adjusted-levelTrend)
+		switch spec.Trend {
+		case AdditiveTrend, AdditiveDampedTrend:
+			state.trend = phiTrend + (params.beta/params.alpha)*(state.level-lastLevel-phiTrend)
+		case MultiplicativeTrend, MultiplicativeDampedTrend:
+			state.trend = phiTrend + (params.beta/params.alpha)*(state.level/lastLevel-phiTrend)
+		}
+		switch spec.Season {
+		case AdditiveSeason:
+			state.seasonals[slot] += params.gamma * (val - levelTrend - state.seasonals[slot])
+		case MultiplicativeSeason:
+			state.seasonals[slot] += params.gamma * (val/levelTrend - state.seasonals[slot])
+		}
+	}
+	// The likelihood of a perfect fit is unbounded, so the variance is not allowed to fall below a minimum
+	scale := float64(1)
+	if spec.Error == AdditiveError && sumSquares > 0 {
+		scale = sumSquares / float64(n)
+	}
+	sse = math.Max(sse, etsMinVariance*scale*float64(n))
+	return state, fitted, sse, float64(n)*math.Log(sse) + 2*sumLogForecast
+}
+
+// initialETSState estimates the initial state of an ETS model from the start of the series, returns an error if the
+// estimated state is not admissible for a multiplicative trend
+func initialETSState(series []float64, seasonLength int, spec ETSSpec) (etsState, error) {
+	state := etsState{}
+
+	// Seasonally adjust the start of the series using normalised seasonal components
+	start := series
+	if length := maxInt(2*seasonLength, 10); len(start) > length {
+		start = series[:length]
+	}
+	adjusted := append([]float64{}, start...)
+	switch spec.Season {
+	case AdditiveSeason:
+		state.seasonals = initialSeasonalComponentsAdditive(series, seasonLength)
+		mean := float64(0)
+		for _, seasonal := range state.seasonals {
+			mean += seasonal / float64(seasonLength)
+		}
+		for i := range state.seasonals {
+			state.seasonals[i] -= mean
+		}
+		for i := range adjusted {
+			adjusted[i] -= state.seasonals[i%seasonLength]
+		}
+	case MultiplicativeSeason:
+		state.seasonals = initialSeasonalComponentsMultiplicative(series, seasonLength)
+		mean := float64(0)
+		for _, seasonal := range state.seasonals {
+			mean += seasonal / float64(seasonLength)
+		}
+		for i := range state.seasonals {
+			state.seasonals[i] /= mean
+		}
+		for i := range adjusted {
+			adjusted[i] /= state.seasonals[i%seasonLength]
+		}
+	}
+
+	// The initial state is the step before the first observation, one step back along the linear fit
+	intercept, slope := linearFit(adjusted)
+	switch spec.Trend {
+	case NoTrend:
+		state.level = initialSeasonAverages(adjusted, len(adjusted))[0]
+	case AdditiveTrend, AdditiveDampedTrend:
+		state.level = intercept - slope
+		state.trend = slope
+	case MultiplicativeTrend, MultiplicativeDampedTrend:
+		state.level = intercept
+		state.trend = 1
+		if intercept > 0 && intercept+slope > 0 {
+			state.trend = (intercept + slope) / intercept
+			state.level = intercept / state.trend
+		}
+	}
+	if spec.multiplicative() && state.level <= 0 {
+		return state, fmt.Errorf("Unable to fit %s to the series, the initial level is not positive", spec)
+	}
+	return state, nil
+}
+
+// validateETSSeries ensures there is at least a full season and two finite observations of data, and that the series is
+// strictly positive if any component of the model is multiplicative
+func validateETSSeries(series []float64, seasonLength int, spec ETSSpec) error {
+	if len(series) < 2 {
+		return &ParamError{Op: "prediction", Name: "series length", Value: len(series), Range: "at least 2", Err: ErrSeriesLength}
+	}
+	err := validateSeriesLength(series, seasonLength)
+	if err != nil {
+		return err
+	}
+	err = validateFiniteObservations(series)
+	if err != nil {
+		return err
+	}
+	if spec.multiplicative() {
+		return validatePositiveSeries(series)
+	}
+	return nil
+}
+
+// validatePositiveSeries ensures every value of the series that is not missing (NaN) is strictly positive
+func validatePositiveSeries(series []float64) error {
+	for i, val := range series {
+		if val <= 0 {
+			return &ParamError{Op: "prediction", Name: fmt.Sprintf("series value %d", i), Value: val, Range: "strictly positive for multiplicative components", Err: ErrSeriesValue}
+		}
+	}
+	return nil
+}
+
+// maxInt returns the larger of two ints
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+// airlineTestSeries is the monthly international airline passengers series of Box and Jenkins, which has a
+// multiplicative seasonal pattern
+var airlineTestSeries = []float64{112, 118, 132, 129, 121, 135, 148, 148, 136, 119, 104, 118, 115, 126, 141, 135, 125, 149,
+	170, 170, 158, 133, 114, 140, 145, 150, 178, 163, 172, 178, 199, 199, 184, 162, 146, 166, 171, 180, 193, 181, 183, 218,
+	230, 242, 209, 191, 172, 194, 196, 196, 236, 235, 229, 243, 264, 272, 237, 211, 180, 201, 204, 188, 235, 227, 234, 264,
+	302, 293, 259, 229, 203, 229, 242, 233, 267, 269, 270, 315, 364, 347, 312, 274, 237, 278, 284, 277, 317, 313, 318, 374,
+	413, 405, 355, 306, 271, 306, 315, 301, 356, 348, 355, 422, 465, 467, 404, 347, 305, 336, 340, 318, 362, 348, 363, 435,
+	491, 505, 404, 359, 310, 337, 360, 342, 406, 396, 420, 472, 548, 559, 463, 407, 362, 405, 417, 391, 419, 461, 472, 535,
+	622, 606, 508, 461, 390, 432}
+
+func TestETSSpecString(t *testing.T) {
+	var tests = []struct {
+		expected string
+		spec     holtwinters.ETSSpec
+	}{
+		{"ETS(A,N,N)", holtwinters.ETSSpec{Error: holtwinters.AdditiveError, Trend: holtwinters.NoTrend, Season: holtwinters.NoSeason}},
+		{"ETS(A,A,A)", holtwinters.ETSSpec{Error: holtwinters.AdditiveError, Trend: holtwinters.AdditiveTrend, Season: holtwinters.AdditiveSeason}},
+		{"ETS(M,Ad,M)", holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.AdditiveDampedTrend, Season: holtwinters.MultiplicativeSeason}},
+		{"ETS(M,M,N)", holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.MultiplicativeTrend, Season: holtwinters.NoSeason}},
+		{"ETS(M,Md,A)", holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.MultiplicativeDampedTrend, Season: holtwinters.AdditiveSeason}},
+		{"ETS(ErrorType(5),TrendType(7),SeasonType(9))", holtwinters.ETSSpec{Error: 5, Trend: 7, Season: 9}},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if test.spec.String() != test.expected {
+				t.Errorf("string mismatch, want %s, got %s", test.expected, test.spec.String())
+			}
+		})
+	}
+}
+
+func TestFitETS(t *testing.T) {
+
+	var tests = []struct {
+		description  string
+		expectedErr  error
+		series       []float64
+		seasonLength int
+		spec         holtwinters.ETSSpec
+	}{
+		{
+			"Fail, unknown error type",
+			&holtwinters.ParamError{Op: "prediction", Name: "error type", Value: 2, Range: "additive or multiplicative"},
+			airlineTestSeries,
+			12,
+			holtwinters.ETSSpec{Error: 2},
+		},
+		{
+			"Fail, unknown trend type",
+			&holtwinters.ParamError{Op: "prediction", Name: "trend type", Value: 5, Range: "none, additive, additive damped, multiplicative or multiplicative damped"},
+			airlineTestSeries,
+			12,
+			holtwinters.ETSSpec{Trend: 5},
+		},
+		{
+			"Fail, unknown season type",
+			&holtwinters.ParamError{Op: "prediction", Name: "season type", Value: 3, Range: "none, additive or multiplicative"},
+			airlineTestSeries,
+			12,
+			holtwinters.ETSSpec{Season: 3},
+		},
+		{
+			"Fail, season length too short",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			airlineTestSeries,
+			1,
+			holtwinters.ETSSpec{Season: holtwinters.AdditiveSeason},
+		},
+		{
+			"Fail, less than a season of data",
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 6, Range: "at least the season length 12", Err: holtwinters.ErrSeriesLength},
+			airlineTestSeries[:6],
+			12,
+			holtwinters.ETSSpec{Season: holtwinters.AdditiveSeason},
+		},
+		{
+			"Fail, single observation",
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeriesLength},
+			[]float64{1},
+			12,
+			holtwinters.ETSSpec{},
+		},
+		{
+			"Fail, multiplicative error with non positive series",
+			&holtwinters.ParamError{Op: "prediction", Name: "series value 2", Value: 0.0, Range: "strictly positive for multiplicative components", Err: holtwinters.ErrSeriesValue},
+			[]float64{1, 2, 0, 2, 1, 2},
+			2,
+			holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError},
+		},
+		{
+			"Success, non seasonal ignores season length",
+			nil,
+			airlineTestSeries,
+			1,
+			holtwinters.ETSSpec{Trend: holtwinters.AdditiveTrend},
+		},
+		{
+			"Success, missing values",
+			nil,
+			append([]float64{math.NaN()}, airlineTestSeries[1:]...),
+			12,
+			holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.AdditiveDampedTrend, Season: holtwinters.MultiplicativeSeason},
+		},
+		{
+			"Success, detected season length",
+			nil,
+			airlineTestSeries,
+			holtwinters.AutoSeasonLength,
+			holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.MultiplicativeTrend, Season: holtwinters.MultiplicativeSeason},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			model, err := holtwinters.FitETS(test.series, test.seasonLength, test.spec)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if err != nil {
+				return
+			}
+			if model.Spec != test.spec {
+				t.Errorf("spec mismatch, want %s, got %s", test.spec, model.Spec)
+			}
+			if model.Alpha <= 0 || model.Alpha >= 1 || model.Beta < 0 || model.Beta >= model.Alpha || model.Gamma < 0 || model.Gamma >= 1-model.Alpha {
+				t.Errorf("coefficients outside of the usual region, alpha: %f, beta: %f, gamma: %f", model.Alpha, model.Beta, model.Gamma)
+			}
+			if len(model.Fitted) != len(test.series) {
+				t.Errorf("fitted length mismatch, want %d, got %d", len(test.series), len(model.Fitted))
+			}
+			forecast, err := model.Forecast(12)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i, val := range forecast {
+				if math.IsNaN(val) || math.IsInf(val, 0) {
+					t.Errorf("forecast %d is not finite: %f", i, val)
+				}
+			}
+		})
+	}
+}
+
+func TestFitETSInformationCriteria(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(1e-12, 0)
+
+	model, err := holtwinters.FitETS(airlineTestSeries, 12, holtwinters.ETSSpec{
+		Error:  holtwinters.MultiplicativeError,
+		Trend:  holtwinters.AdditiveTrend,
+		Season: holtwinters.MultiplicativeSeason,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// alpha, beta and gamma, the initial level and trend, 11 free seasonal components and the variance
+	if model.Parameters != 17 {
+		t.Errorf("parameters mismatch, want 17, got %d", model.Parameters)
+	}
+	n := float64(len(airlineTestSeries))
+	k := float64(model.Parameters)
+	sumSquares := float64(0)
+	sumLog := float64(0)
+	for i, val := range airlineTestSeries {
+		relative := (val - model.Fitted[i]) / model.Fitted[i]
+		sumSquares += relative * relative
+		sumLog += math.Log(model.Fitted[i])
+	}
+	logLikelihood := -0.5*n*(math.Log(2*math.Pi*sumSquares/n)+1) - sumLog
+	expected := []float64{sumSquares / n, logLikelihood, -2*logLikelihood + 2*k, -2*logLikelihood + 2*k + 2*k*(k+1)/(n-k-1), -2*logLikelihood + math.Log(n)*k}
+	got := []float64{model.Variance, model.LogLikelihood, model.AIC, model.AICc, model.BIC}
+	if !cmp.Equal(expected, got, equateApprox) {
+		t.Errorf("information criteria mismatch (-want +got):\n%s", cmp.Diff(expected, got, equateApprox))
+	}
+}
+
+func TestETSModelForecast(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		model       *holtwinters.ETSModel
+	}{
+		{
+			"No trend, additive season",
+			[]float64{12, 8, 12},
+			&holtwinters.ETSModel{Spec: holtwinters.ETSSpec{Season: holtwinters.AdditiveSeason}, SeasonLength: 2, Phi: 1, Level: 10,
+				Seasonals: []float64{-2, 2}, Observations: 3},
+		},
+		{
+			"Additive damped trend",
+			[]float64{10 + 0.5*2, 10 + 0.75*2, 10 + 0.875*2},
+			&holtwinters.ETSModel{Spec: holtwinters.ETSSpec{Trend: holtwinters.AdditiveDampedTrend}, SeasonLength: 1, Phi: 0.5, Level: 10,
+				Trend: 2, Observations: 3},
+		},
+		{
+			"Multiplicative trend, multiplicative season",
+			[]float64{10 * 2 * 0.5, 10 * 4 * 1.5, 10 * 8 * 0.5},
+			&holtwinters.ETSModel{Spec: holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.MultiplicativeTrend,
+				Season: holtwinters.MultiplicativeSeason}, SeasonLength: 2, Phi: 1, Level: 10, Trend: 2, Seasonals: []float64{0.5, 1.5}, Observations: 4},
+		},
+		{
+			"Multiplicative damped trend",
+			[]float64{10 * math.Pow(4, 0.5), 10 * math.Pow(4, 0.75)},
+			&holtwinters.ETSModel{Spec: holtwinters.ETSSpec{Error: holtwinters.MultiplicativeError, Trend: holtwinters.MultiplicativeDampedTrend},
+				SeasonLength: 1, Phi: 0.5, Level: 10, Trend: 4, Observations: 4},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.model.Forecast(len(test.expected))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(test.expected, result, equateApprox) {
+				t.Errorf("forecast mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox))
+			}
+		})
+	}
+
+	_, err := (&holtwinters.ETSModel{}).Forecast(1)
+	if err == nil || err.Error() != "Model must be fitted before forecasting" {
+		t.Errorf("expected unfitted error, got %v", err)
+	}
+}
+
+func TestSelectETS(t *testing.T) {
+
+	var tests = []struct {
+		description    string
+		expectedSeason holtwinters.SeasonType
+		expectedErr    error
+		series         []float64
+		seasonLength   int
+	}{
+		{
+			"Fail, less than a season of data",
+			holtwinters.NoSeason,
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 6, Range: "at least the season length 12", Err: holtwinters.ErrSeriesLength},
+			airlineTestSeries[:6],
+			12,
+		},
+		{
+			"Success, multiplicative seasonality chosen for airline passengers",
+			holtwinters.MultiplicativeSeason,
+			nil,
+			airlineTestSeries,
+			12,
+		},
+		{
+			"Success, non seasonal only",
+			holtwinters.NoSeason,
+			nil,
+			airlineTestSeries,
+			1,
+		},
+		{
+			"Success, additive seasonality chosen for series with negative values",
+			holtwinters.AdditiveSeason,
+			nil,
+			[]float64{-5, 5, 10, 5, -5, -4, 6, 9, 5, -6, -5, 5, 11, 4, -5, -4, 5, 10, 6, -5},
+			5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			model, err := holtwinters.SelectETS(test.series, test.seasonLength)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if err != nil {
+				return
+			}
+			if model.Spec.Season != test.expectedSeason {
+				t.Errorf("season type mismatch, want %s, got %s", test.expectedSeason, model.Spec.Season)
+			}
+
+			// The selected model must have the lowest AICc of every model that could be fitted
+			for _, spec := range []holtwinters.ETSSpec{
+				{Error: holtwinters.AdditiveError, Trend: holtwinters.NoTrend, Season: holtwinters.NoSeason},
+				{Error: holtwinters.AdditiveError, Trend: holtwinters.AdditiveDampedTrend, Season: test.expectedSeason},
+			} {
+				other, err := holtwinters.FitETS(test.series, test.seasonLength, spec)
+				if err != nil {
+					continue
+				}
+				if other.AICc < model.AICc {
+					t.Errorf("%s has a lower AICc than the selected %s, %f < %f", spec, model.Spec, other.AICc, model.AICc)
+				}
+			}
+		})
+	}
+}
+
+func TestETSPerfectFit(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-6)
+	linear := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	constant := []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+
+	model, err := holtwinters.FitETS(linear, 1, holtwinters.ETSSpec{Error: holtwinters.AdditiveError, Trend: holtwinters.AdditiveTrend})
+	if err != nil {
+		t.Fatalf("unexpected error fitting linear series: %v", err)
+	}
+	if math.IsInf(model.LogLikelihood, 0) || math.IsNaN(model.LogLikelihood) {
+		t.Errorf("expected finite log-likelihood for linear series, got %f", model.LogLikelihood)
+	}
+	forecast, err := model.Forecast(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal([]float64{13, 14, 15}, forecast, equateApprox) {
+		t.Errorf("linear forecast mismatch (-want +got):\n%s", cmp.Diff([]float64{13, 14, 15}, forecast, equateApprox))
+	}
+
+	for _, series := range [][]float64{linear, constant} {
+		model, err = holtwinters.SelectETS(series, 12)
+		if err != nil {
+			t.Fatalf("unexpected error selecting model for %v: %v", series, err)
+		}
+		if math.IsInf(model.AICc, -1) || math.IsNaN(model.AICc) {
+			t.Errorf("expected finite AICc for %v, got %f", series, model.AICc)
+		}
+	}
+
+	model, err = holtwinters.SelectETS(constant, 12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forecast, err = model.Forecast(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal([]float64{5, 5, 5}, forecast, equateApprox) {
+		t.Errorf("constant forecast mismatch (-want +got):\n%s", cmp.Diff([]float64{5, 5, 5}, forecast, equateApprox))
+	}
+}
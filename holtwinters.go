@@ -18,55 +18,55 @@ limitations under the License.
 // to make predictions and to smooth results for a time series.
 // Built using these articles https://grisha.org/blog/2016/01/29/triple-exponential-smoothing-forecasting/
 // Thanks to the author, Gregory Trubetskoy
+//
+// Missing observations can be provided as NaN, they are skipped when calculating the initial trend and seasonal
+// components, and when smoothing the state is not updated for them, instead the level is moved forward by the trend as
+// if the observation had been the same as the forecast for it. Any other non-finite observation, ±Inf, is treated as
+// missing in the same way, a Model's NonFinite policy can be set to reject or impute them instead. Parameters must be
+// finite, and an error wrapping ErrNonFiniteResult is returned rather than a non-finite smoothed value or prediction.
 package holtwinters
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // PredictAdditive takes in a seasonal historical series of data and produces a prediction of what the data will be in the future using triple
 // exponential smoothing using the additive method. Existing data will also be smoothed alongside predictions. Returns the entire dataset with
 // the predictions appended to the end.
 // series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
 // the first value should be at the start of a season
-// seasonLength - The length of the data's seasons, must be at least 2
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
 // alpha - Exponential smoothing coefficient for level, must be between 0 and 1
 // beta - Exponential smoothing coefficient for trend, must be between 0 and 1
 // gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
 // predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
 func PredictAdditive(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error) {
-	// Parameter validation mainly to avoid out of bounds errors and division by zero
-	err := validateParams(series, seasonLength, alpha, beta, gamma, predictionLength)
-	if err != nil {
-		return nil, err
-	}
-
-	// Assumptions at this point, after params have been validated
-	// seasonLength >= 2
-	// series >= seasonLength
-	// alpha, beta, gamma >= 0.0 and <= 1.0
-
-	// Initial setup
-	result := []float64{series[0]}
-	smooth := series[0]
-	trend := initialTrend(series, seasonLength)
-	seasonals := initialSeasonalComponentsAdditive(series, seasonLength)
+	return PredictAdditiveDamped(series, seasonLength, alpha, beta, gamma, 1, predictionLength)
+}
 
-	// Build prediction and smooth existing values
-	for i := 1; i < len(series)+predictionLength; i++ {
-		if i >= len(series) {
-			// Prediction
-			m := float64(i - len(series) + 1)
-			result = append(result, (smooth+m*trend)+seasonals[i%seasonLength])
-		} else {
-			// Smooth existing values
-			val := series[i]
-			lastSmooth := smooth
-			smooth = alpha*(val-seasonals[i%seasonLength]) + (1-alpha)*(smooth+trend)
-			trend = beta*(smooth-lastSmooth) + (1-beta)*trend
-			seasonals[i%seasonLength] = gamma*(val-smooth) + (1-gamma)*seasonals[i%seasonLength]
-			result = append(result, smooth+trend+seasonals[i%seasonLength])
-		}
-	}
-	return result, nil
+// PredictAdditiveDamped takes in a seasonal historical series of data and produces a prediction of what the data will be in the future using triple
+// exponential smoothing using the additive method with a damped trend. The trend is multiplied by phi at each step, so rather than being
+// extrapolated linearly forever the forecasts flatten out over longer horizons. Existing data will also be smoothed alongside predictions.
+// Returns the entire dataset with the predictions appended to the end.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// phi - Damping coefficient for trend, must be greater than 0 and at most 1, 1 applies no damping
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+func PredictAdditiveDamped(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, phi float64, predictionLength int) ([]float64, error) {
+	return predict(series, Config{
+		Method:           Additive,
+		SeasonLength:     seasonLength,
+		Alpha:            alpha,
+		Beta:             beta,
+		Gamma:            gamma,
+		Phi:              phi,
+		PredictionLength: predictionLength,
+	}, phi)
 }
 
 // PredictMultiplicative takes in a seasonal historical series of data and produces a prediction of what the data will be in the future using triple
@@ -74,22 +74,69 @@ func PredictAdditive(series []float64, seasonLength int, alpha float64, beta flo
 // the predictions appended to the end.
 // series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
 // the first value should be at the start of a season
-// seasonLength - The length of the data's seasons, must be at least 2
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
 // alpha - Exponential smoothing coefficient for level, must be between 0 and 1
 // beta - Exponential smoothing coefficient for trend, must be between 0 and 1
 // gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
 // predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
 func PredictMultiplicative(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error) {
+	return PredictMultiplicativeDamped(series, seasonLength, alpha, beta, gamma, 1, predictionLength)
+}
+
+// PredictMultiplicativeDamped takes in a seasonal historical series of data and produces a prediction of what the data will be in the future using
+// triple exponential smoothing using the multiplicative method with a damped trend. The trend is multiplied by phi at each step, so rather than
+// being extrapolated linearly forever the forecasts flatten out over longer horizons. Existing data will also be smoothed alongside predictions.
+// Returns the entire dataset with the predictions appended to the end.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// phi - Damping coefficient for trend, must be greater than 0 and at most 1, 1 applies no damping
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+func PredictMultiplicativeDamped(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, phi float64, predictionLength int) ([]float64, error) {
+	return predict(series, Config{
+		Method:           Multiplicative,
+		SeasonLength:     seasonLength,
+		Alpha:            alpha,
+		Beta:             beta,
+		Gamma:            gamma,
+		Phi:              phi,
+		PredictionLength: predictionLength,
+	}, phi)
+}
+
+// PredictMultiplicativeLegacy reproduces the results of PredictMultiplicative prior to v0.3.0, which added the seasonal component to the forecast
+// rather than multiplying by it, and multiplied only the trend by the seasonal component when smoothing existing values. These are not the
+// Holt-Winters multiplicative recurrences and flatten forecasts for series with a large seasonal amplitude; this is only provided for callers that
+// depend on the old values, new code should use PredictMultiplicative. Missing values were not supported prior to v0.3.0, so every value of the
+// series must be finite, and an error wrapping ErrNonFiniteResult is returned rather than a non-finite smoothed value or prediction.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season, every value must be finite
+// seasonLength - The length of the data's seasons, must be at least 2
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+//
+// Deprecated: Use PredictMultiplicative.
+func PredictMultiplicativeLegacy(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) ([]float64, error) {
 	// Parameter validation mainly to avoid out of bounds errors and division by zero
 	err := validateParams(series, seasonLength, alpha, beta, gamma, predictionLength)
 	if err != nil {
 		return nil, err
 	}
+	_, err = applyNonFinitePolicy(series, RejectNonFinite)
+	if err != nil {
+		return nil, err
+	}
 
 	// Assumptions at this point, after params have been validated
 	// seasonLength >= 2
 	// series >= seasonLength
 	// alpha, beta, gamma >= 0.0 and <= 1.0
+	// every value of the series is finite
 
 	// Initial setup
 	result := []float64{series[0]}
@@ -113,93 +160,194 @@ func PredictMultiplicative(series []float64, seasonLength int, alpha float64, be
 			result = append(result, smooth+trend*seasonals[i%seasonLength])
 		}
 	}
+	err = validateFinite(result)
+	if err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
 // initialTrend calculates the initial trend based on average trends between the first and second
 // seasons, if there is not enough data for two full seasons to be compared, instead the trend is
-// calculated by comparing the first and second points of the first season
+// calculated by comparing the first and second points of the first season. Missing (NaN) values
+// are skipped, only comparing points that are both present
 func initialTrend(series []float64, seasonLength int) float64 {
 	// If not enough data to compare two seasons, more rough trend calculated using first two points
 	if len(series) < seasonLength*2 {
-		return series[1] - series[0]
+		return initialTrendFirstPoints(series)
 	}
 
 	// Enough data for two seasons, compare first two and average for trend
 	sum := float64(0)
+	compared := 0
 	for i := 0; i < seasonLength; i++ {
+		if missing(series[i+seasonLength]) || missing(series[i]) {
+			continue
+		}
 		sum += (series[i+seasonLength] - series[i]) / float64(seasonLength)
+		compared++
+	}
+	if compared == 0 {
+		return initialTrendFirstPoints(series)
 	}
-	return sum / float64(seasonLength)
+	return sum / float64(compared)
 }
 
-// validateParams ensures the parameters provided are valid, avoids NaN values and out of bounds errors
+// initialTrendFirstPoints calculates a rough initial trend using the first two points that are not missing (NaN), 0 if
+// there are not two points
+func initialTrendFirstPoints(series []float64) float64 {
+	first := -1
+	for i, val := range series {
+		if missing(val) {
+			continue
+		}
+		if first == -1 {
+			first = i
+			continue
+		}
+		return (val - series[first]) / float64(i-first)
+	}
+	return 0
+}
+
+// initialLevel calculates the initial level, the first value of the series that is not missing (NaN), 0 if every value
+// is missing
+func initialLevel(series []float64) float64 {
+	for _, val := range series {
+		if !missing(val) {
+			return val
+		}
+	}
+	return 0
+}
+
+// dampedTrendMultiplier calculates the sum phi+phi^2+...+phi^m, the multiplier applied to the trend when forecasting m steps
+// ahead with a damped trend, with no damping (phi of 1) this is m
+func dampedTrendMultiplier(phi float64, m int) float64 {
+	sum := float64(0)
+	damping := float64(1)
+	for i := 0; i < m; i++ {
+		damping *= phi
+		sum += damping
+	}
+	return sum
+}
+
+// validateParams ensures the parameters provided are valid, avoids NaN values and out of bounds errors, the comparisons
+// are written so that NaN parameters fail them
 func validateParams(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int) error {
 	if seasonLength <= 1 {
-		return fmt.Errorf("Invalid parameter for prediction; season length must be at least 2, is %d", seasonLength)
+		return &ParamError{Op: "prediction", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
 	}
 	if predictionLength < 0 {
-		return fmt.Errorf("Invalid parameter for prediction; prediction length must be at least 0, cannot be negative, is %d", predictionLength)
+		return &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
 	}
-	if alpha < 0.0 || alpha > 1.0 {
-		return fmt.Errorf("Invalid parameter for prediction; alpha must be between 0 and 1, is %f", alpha)
+	err := validateSmoothingParams(alpha, beta, gamma)
+	if err != nil {
+		return err
 	}
-	if beta < 0.0 || beta > 1.0 {
-		return fmt.Errorf("Invalid parameter for prediction; beta must be between 0 and 1, is %f", beta)
+	return validateSeriesLength(series, seasonLength)
+}
+
+// validateSmoothingParams ensures the exponential smoothing coefficients provided are valid
+func validateSmoothingParams(alpha float64, beta float64, gamma float64) error {
+	if !(alpha >= 0.0 && alpha <= 1.0) {
+		return &ParamError{Op: "prediction", Name: "alpha", Value: alpha, Range: "between 0 and 1", Err: ErrAlpha}
+	}
+	if !(beta >= 0.0 && beta <= 1.0) {
+		return &ParamError{Op: "prediction", Name: "beta", Value: beta, Range: "between 0 and 1", Err: ErrBeta}
 	}
-	if gamma < 0.0 || gamma > 1.0 {
-		return fmt.Errorf("Invalid parameter for prediction; gamma must be between 0 and 1, is %f", gamma)
+	if !(gamma >= 0.0 && gamma <= 1.0) {
+		return &ParamError{Op: "prediction", Name: "gamma", Value: gamma, Range: "between 0 and 1", Err: ErrGamma}
 	}
+	return nil
+}
+
+// validateSeriesLength ensures there is at least a full season of data
+func validateSeriesLength(series []float64, seasonLength int) error {
 	if len(series) < seasonLength {
-		return fmt.Errorf("Invalid parameter for prediction; must have at least 1 season of data to predict, season length: %d, series length: %d", seasonLength, len(series))
+		return &ParamError{Op: "prediction", Name: "series length", Value: len(series), Range: fmt.Sprintf("at least the season length %d", seasonLength), Err: ErrSeriesLength}
 	}
 	return nil
 }
 
-// initialSeasonalComponentsAdditive calculates the initial seasonal values for the additive method
+// validateDampingParam ensures the damping coefficient provided is valid
+func validateDampingParam(phi float64) error {
+	if !(phi > 0.0 && phi <= 1.0) {
+		return &ParamError{Op: "prediction", Name: "phi", Value: phi, Range: "greater than 0 and at most 1", Err: ErrPhi}
+	}
+	return nil
+}
+
+// initialSeasonalComponentsAdditive calculates the initial seasonal values for the additive method, skipping missing (NaN)
+// values, a seasonal value with no values present is 0
 func initialSeasonalComponentsAdditive(series []float64, seasonLength int) []float64 {
 	var seasonals = make([]float64, seasonLength)
-	seasonAverages := []float64{}
-	nSeasons := len(series) / seasonLength
-	for i := 0; i < nSeasons; i++ {
-		// Calculate sum of season
-		sum := float64(0)
-		for j := seasonLength * i; j < seasonLength*i+seasonLength; j++ {
-			sum += series[j]
-		}
-		// Calculate average of season and add to slice
-		seasonAverages = append(seasonAverages, sum/float64(seasonLength))
-	}
+	seasonAverages := initialSeasonAverages(series, seasonLength)
 	for i := 0; i < seasonLength; i++ {
 		sumOfValuesOverAverage := float64(0)
-		for j := 0; j < nSeasons; j++ {
-			sumOfValuesOverAverage += series[seasonLength*j+i] - seasonAverages[j]
+		nSeasons := 0
+		for j := range seasonAverages {
+			val := series[seasonLength*j+i]
+			if missing(val) || missing(seasonAverages[j]) {
+				continue
+			}
+			sumOfValuesOverAverage += val - seasonAverages[j]
+			nSeasons++
+		}
+		if nSeasons > 0 {
+			seasonals[i] = sumOfValuesOverAverage / float64(nSeasons)
 		}
-		seasonals[i] = sumOfValuesOverAverage / float64(nSeasons)
 	}
 	return seasonals
 }
 
-// initialSeasonalComponentsMultiplicative calculates the initial seasonal values for the multiplicative method
+// initialSeasonalComponentsMultiplicative calculates the initial seasonal values for the multiplicative method, skipping
+// missing (NaN) values, a seasonal value with no values present is 1
 func initialSeasonalComponentsMultiplicative(series []float64, seasonLength int) []float64 {
 	var seasonals = make([]float64, seasonLength)
+	seasonAverages := initialSeasonAverages(series, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		sumOfValuesOverAverage := float64(0)
+		nSeasons := 0
+		for j := range seasonAverages {
+			val := series[seasonLength*j+i]
+			if missing(val) || missing(seasonAverages[j]) {
+				continue
+			}
+			sumOfValuesOverAverage += val / seasonAverages[j]
+			nSeasons++
+		}
+		seasonals[i] = 1
+		if nSeasons > 0 {
+			seasonals[i] = sumOfValuesOverAverage / float64(nSeasons)
+		}
+	}
+	return seasonals
+}
+
+// initialSeasonAverages calculates the average of each full season in the series, skipping missing (NaN) values, the
+// average of a season with no values present is NaN
+func initialSeasonAverages(series []float64, seasonLength int) []float64 {
 	seasonAverages := []float64{}
 	nSeasons := len(series) / seasonLength
 	for i := 0; i < nSeasons; i++ {
 		// Calculate sum of season
 		sum := float64(0)
+		present := 0
 		for j := seasonLength * i; j < seasonLength*i+seasonLength; j++ {
+			if missing(series[j]) {
+				continue
+			}
 			sum += series[j]
+			present++
 		}
 		// Calculate average of season and add to slice
-		seasonAverages = append(seasonAverages, sum/float64(seasonLength))
-	}
-	for i := 0; i < seasonLength; i++ {
-		sumOfValuesOverAverage := float64(0)
-		for j := 0; j < nSeasons; j++ {
-			sumOfValuesOverAverage += series[seasonLength*j+i] / seasonAverages[j]
+		if present == 0 {
+			seasonAverages = append(seasonAverages, math.NaN())
+			continue
 		}
-		seasonals[i] = sumOfValuesOverAverage / float64(nSeasons)
+		seasonAverages = append(seasonAverages, sum/float64(present))
 	}
-	return seasonals
+	return seasonAverages
 }
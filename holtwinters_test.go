@@ -17,20 +17,17 @@ limitations under the License.
 package holtwinters_test
 
 import (
-	"errors"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/jthomperoo/holtwinters"
 )
 
 func TestPredictMultiplicative(t *testing.T) {
-	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
-		if x == nil || y == nil {
-			return x == nil && y == nil
-		}
-		return x.Error() == y.Error()
-	})
+	// Expected values are generated by testdata/statsmodels_reference.py
+	equateReference := cmpopts.EquateApprox(0, 1e-9)
 
 	var tests = []struct {
 		description      string
@@ -46,7 +43,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, season length too short",
 			nil,
-			errors.New(`Invalid parameter for prediction; season length must be at least 2, is 1`),
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
 			[]float64{1, 2, 3, 2, 1},
 			1,
 			0.9,
@@ -57,7 +54,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, negative prediction length",
 			nil,
-			errors.New(`Invalid parameter for prediction; prediction length must be at least 0, cannot be negative, is -3`),
+			&holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -3, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -68,7 +65,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, alpha too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; alpha must be between 0 and 1, is 1.500000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			1.5,
@@ -79,7 +76,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, alpha too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; alpha must be between 0 and 1, is -0.200000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: -0.200000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			-0.2,
@@ -90,7 +87,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, beta too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; beta must be between 0 and 1, is 2.300000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: 2.300000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -101,7 +98,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, beta too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; beta must be between 0 and 1, is -5.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: -5.000000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -112,7 +109,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, gamma too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; gamma must be between 0 and 1, is 30.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 30.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -123,7 +120,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, gamma too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; gamma must be between 0 and 1, is -20.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: -20.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -134,7 +131,7 @@ func TestPredictMultiplicative(t *testing.T) {
 		{
 			"Fail, data provided less than full season",
 			nil,
-			errors.New(`Invalid parameter for prediction; must have at least 1 season of data to predict, season length: 5, series length: 3`),
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
 			[]float64{1, 2, 3},
 			5,
 			0.9,
@@ -142,6 +139,247 @@ func TestPredictMultiplicative(t *testing.T) {
 			0.9,
 			5,
 		},
+		{
+			"Success, 1 season, no prediction",
+			[]float64{1, 2.6875333333333327, 2.3990147705041394, 1.777577751040615, 0.9938269610471768},
+			nil,
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0,
+		},
+		{
+			"Success, 1 and a half seasons data",
+			[]float64{1, 2.6875333333333327, 2.3990147705041394, 1.777577751040615, 0.9938269610471768, 1.2911670266126047, 1.704847639923613, 5.043305401828334,
+				3.116852116406088, 2.0748165218478754, 2.6263422127547833, 4.437657115938994, 8.766716608248073},
+			nil,
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			5,
+		},
+		{
+			"Success, less than 2 seasons data",
+			[]float64{1, 2.6875333333333327, 2.3990147705041394, 1.777577751040615, 0.9938269610471768, 0.9841554233333333, 1.7642915633777776, 2.0318675857103083,
+				1.6889202902740699, 0.9341887675498806},
+			nil,
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			5,
+		},
+		{
+			"Success, 2 seasons data",
+			[]float64{1, 4.714740182025805, 2.5100430191929775, 1.7775354688824487, 0.9552106141209273, 1.3038322137956542, 0.4291204196582337, 22.350187198191506,
+				1.5201482683301344, 0.8801246049630194, 1.1363795830935541, 1.6077106773393448, 9.222505454231156, 0.7740140236840267, 0.4647757248908077},
+			nil,
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			5,
+		},
+		{
+			"Success, more than 2 seasons data",
+			[]float64{30, 29.847205406830174, 27.688741664511774, 29.780331260659658, 37.46051408006071, 48.67430040744079, 48.5733972214174, 47.65670117563851,
+				40.16550847434178, 38.49607890307329, 30.082484247398853, 25.262768454966384, 13.828528959797147, 5.505250258589424, 31.992001139640283, 28.126597215074128,
+				24.68030764056513, 34.359813126460814, 43.10409020136572, 37.34141816293544, 25.17996690862504, 34.51146500433256, 29.523707538177757, 26.60874892115227,
+				24.25910006840917, 12.954355446239585, 12.453222291648993, 15.808742746550452, 53.53707801726148, 31.38779007607261, 37.48129153874886, 35.85623842598193,
+				19.432263893775644, 21.146214996080822, 21.99386961082193, 21.53667956768427, 18.393629482543844, 20.10656078013632, 14.912536356107408, 15.216413660488776,
+				18.130862613002765, 38.366676550975946, 45.54895699035839, 27.455560630293505, 19.966856095472433, 23.470351269789706, 14.631190408357366, 34.86067352198214,
+				15.889759119968593, 6.846830908107627, 29.41098141537538, 22.988688478998334, 23.984764142391274, 24.204580297096662, 53.09414650600808, 35.65534570469187,
+				22.75190764632207, 28.17779673187628, 22.407044151069577, 20.992333651611155, 20.862426156339982, 6.96445640087764, 14.584014265755167, 30.80563755225048,
+				39.26950374550378, 31.238399107179948, 33.017246057283785, 43.83183540281644, 38.55572755759336, 26.68620254053387, 27.048205652276064, 32.798945329823255,
+				27.154698122978107, 10.43217746747063, 25.514411363351563, 36.65341933960422, 36.832123405309815, 31.933603655060097, 45.1642619011538, 51.93824530216894,
+				36.685384696704645, 28.594649995359692, 27.862027641600115, 32.970727337589985, 27.30984796421256, 10.491753913412703, 25.660050650848525, 36.86254193162436,
+				37.042165711654135, 32.115624704396176, 45.42157527533367, 52.23401152384541, 36.89419327342393, 28.757329969110607, 28.020464483841813, 33.15812589151732},
+			nil,
+			[]float64{30, 21, 29, 31, 40, 48, 53, 47, 37, 39, 31, 29, 17, 9, 20, 24, 27, 35, 41, 38,
+				27, 31, 27, 26, 21, 13, 21, 18, 33, 35, 40, 36, 22, 24, 21, 20, 17, 14, 17, 19,
+				26, 29, 40, 31, 20, 24, 18, 26, 17, 9, 17, 21, 28, 32, 46, 33, 23, 28, 22, 27,
+				18, 8, 17, 21, 31, 34, 44, 38, 31, 30, 26, 32},
+			12,
+			0.716,
+			0.029,
+			0.993,
+			24,
+		},
+		{
+			"Success, large seasonal amplitude with trend",
+			[]float64{100, 270.58149980463764, 549.101130134192, 238.71769500046983, 106.77184446251032, 141.50855900292646, 305.91241201673597, 635.6224630431464,
+				271.63502781704756, 125.55224862969352, 167.85476922196682, 349.8431761510926, 760.4584876334472, 329.5132053102685, 155.36589504692662, 183.6794557189131,
+				426.247720045824, 933.2032316842082, 394.6312206144263, 179.76592980424078, 219.73776425561246, 506.76362885134915, 1103.0631912601516, 463.9382515901664,
+				210.2659732508835},
+			nil,
+			[]float64{100, 220, 480, 210, 95, 130, 270, 610, 260, 120, 160, 330, 740, 320, 150},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			10,
+		},
+		// statsmodels does not support missing values, so these are the package's own results rather than reference values
+		{
+			"Success, missing values",
+			[]float64{2, 2.0157249877418457, 2.9824367605864004, 1.9807494542230815, 0.9895385081961876, 1.0894413088261896, 1.937789572918284, 3.0949747927457474,
+				2.111069478577457, 1.1113129980483303, 1.0128028718558384, 2.0911856150013644, 3.0032159089460686, 2.0288163206977643, 0.9946529211542178, 1.0064294279260524,
+				1.9622476717536783, 2.866135093224908, 1.918686929821235, 0.959282625519536},
+			nil,
+			[]float64{math.NaN(), 2, 3, 2, 1, 1.1, math.NaN(), 3.1, 2.1, 1.1, 1, 2.1, 3, math.NaN(), 1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			prediction, err := holtwinters.PredictMultiplicative(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.predictionLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, prediction, equateReference) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, prediction, equateReference))
+			}
+		})
+	}
+
+}
+
+func TestPredictMultiplicativeLegacy(t *testing.T) {
+
+	var tests = []struct {
+		description      string
+		expected         []float64
+		expectedErr      error
+		series           []float64
+		seasonLength     int
+		alpha            float64
+		beta             float64
+		gamma            float64
+		predictionLength int
+	}{
+		{
+			"Fail, season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			[]float64{1, 2, 3, 2, 1},
+			1,
+			0.9,
+			0.9,
+			0.9,
+			3,
+		},
+		{
+			"Fail, negative prediction length",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -3, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			-3,
+		},
+		{
+			"Fail, alpha too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			1.5,
+			0.9,
+			0.9,
+			3,
+		},
+		{
+			"Fail, alpha too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: -0.200000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			-0.2,
+			0.9,
+			0.9,
+			3,
+		},
+		{
+			"Fail, beta too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: 2.300000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			2.3,
+			0.9,
+			3,
+		},
+		{
+			"Fail, beta too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: -5.000000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			-5,
+			0.9,
+			3,
+		},
+		{
+			"Fail, gamma too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 30.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			30,
+			3,
+		},
+		{
+			"Fail, gamma too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: -20.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			-20,
+			3,
+		},
+		{
+			"Fail, data provided less than full season",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
+			[]float64{1, 2, 3},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			5,
+		},
+		{
+			"Fail, missing value",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series value 2", Value: math.NaN(), Range: "finite", Err: holtwinters.ErrSeriesValue},
+			[]float64{1, 2, math.NaN(), 2, 1, 1, 2, 3},
+			4,
+			0.9,
+			0.9,
+			0.9,
+			3,
+		},
 		{
 			"Success, 1 season, no prediction",
 			[]float64{1, 2.74190231990232, 2.114405995333546, 1.7763863919863403, 1.7832769573623406},
@@ -217,10 +455,10 @@ func TestPredictMultiplicative(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			prediction, err := holtwinters.PredictMultiplicative(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.predictionLength)
+			prediction, err := holtwinters.PredictMultiplicativeLegacy(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.predictionLength)
 
-			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
-				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
 				return
 			}
 
@@ -233,12 +471,6 @@ func TestPredictMultiplicative(t *testing.T) {
 }
 
 func TestPredictAdditive(t *testing.T) {
-	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
-		if x == nil || y == nil {
-			return x == nil && y == nil
-		}
-		return x.Error() == y.Error()
-	})
 
 	var tests = []struct {
 		description      string
@@ -254,7 +486,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, season length too short",
 			nil,
-			errors.New(`Invalid parameter for prediction; season length must be at least 2, is 1`),
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
 			[]float64{1, 2, 3, 2, 1},
 			1,
 			0.9,
@@ -265,7 +497,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, negative prediction length",
 			nil,
-			errors.New(`Invalid parameter for prediction; prediction length must be at least 0, cannot be negative, is -3`),
+			&holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -3, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -273,10 +505,21 @@ func TestPredictAdditive(t *testing.T) {
 			0.9,
 			-3,
 		},
+		{
+			"Fail, season length too short and negative prediction length",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			[]float64{1, 2, 3},
+			1,
+			0.9,
+			0.9,
+			0.9,
+			-3,
+		},
 		{
 			"Fail, alpha too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; alpha must be between 0 and 1, is 1.500000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			1.5,
@@ -287,7 +530,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, alpha too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; alpha must be between 0 and 1, is -0.200000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: -0.200000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			-0.2,
@@ -298,7 +541,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, beta too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; beta must be between 0 and 1, is 2.300000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: 2.300000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -309,7 +552,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, beta too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; beta must be between 0 and 1, is -5.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: -5.000000, Range: "between 0 and 1", Err: holtwinters.ErrBeta},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -320,7 +563,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, gamma too high",
 			nil,
-			errors.New(`Invalid parameter for prediction; gamma must be between 0 and 1, is 30.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 30.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -331,7 +574,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, gamma too low",
 			nil,
-			errors.New(`Invalid parameter for prediction; gamma must be between 0 and 1, is -20.000000`),
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: -20.000000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
 			[]float64{1, 2, 3, 2, 1},
 			5,
 			0.9,
@@ -342,7 +585,7 @@ func TestPredictAdditive(t *testing.T) {
 		{
 			"Fail, data provided less than full season",
 			nil,
-			errors.New(`Invalid parameter for prediction; must have at least 1 season of data to predict, season length: 5, series length: 3`),
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
 			[]float64{1, 2, 3},
 			5,
 			0.9,
@@ -420,13 +663,26 @@ func TestPredictAdditive(t *testing.T) {
 			0.993,
 			24,
 		},
+		{
+			"Success, missing values",
+			[]float64{2, 2.047375, 3.00458125, 1.9787471875, 0.984018078125, 1.07592581171875, 1.9574434981770834, 3.0654344490625, 2.095761878265625, 1.120340320502344, 1.0491261249516015,
+				2.0669619610897403, 3.018031995651598, 2.0078878503776543, 0.9940385138390088, 1.0442246409180322, 1.9837683348937745, 2.9760007509734443, 1.9563753840480211,
+				0.9561136272330913},
+			nil,
+			[]float64{math.NaN(), 2, 3, 2, 1, 1.1, math.NaN(), 3.1, 2.1, 1.1, 1, 2.1, 3, math.NaN(), 1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			5,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
 			prediction, err := holtwinters.PredictAdditive(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.predictionLength)
 
-			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
-				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
 				return
 			}
 
@@ -437,3 +693,252 @@ func TestPredictAdditive(t *testing.T) {
 	}
 
 }
+
+func TestPredictAdditiveDamped(t *testing.T) {
+	// Expected values are generated by testdata/statsmodels_reference.py
+	equateReference := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description      string
+		expected         []float64
+		expectedErr      error
+		series           []float64
+		seasonLength     int
+		alpha            float64
+		beta             float64
+		gamma            float64
+		phi              float64
+		predictionLength int
+	}{
+		{
+			"Fail, alpha too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			1.5,
+			0.9,
+			0.9,
+			0.9,
+			3,
+		},
+		{
+			"Fail, phi too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 1.200000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			1.2,
+			3,
+		},
+		{
+			"Fail, phi zero",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 0.000000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0,
+			3,
+		},
+		{
+			"Fail, phi too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: -0.500000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			-0.5,
+			3,
+		},
+		{
+			"Fail, phi zero and data provided less than full season",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
+			[]float64{1, 2, 3},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0,
+			3,
+		},
+		{
+			"Success, 2 seasons data",
+			[]float64{1, 2.5629951999999996, 3.0843467903999997, 1.9754157751808, 0.9870570321135613, 1.1625051100781536, 1.8038083571706092, 3.2166821084647417,
+				2.113240328637994, 1.0851112479198648, 1.0977207146804588, 2.032823104968913, 3.0404212313961567, 2.0336729301568597, 1.0490450923732026},
+			nil,
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0.8,
+			5,
+		},
+		{
+			"Success, 3 seasons data with trend, long horizon",
+			[]float64{100, 210.08190000000002, 509.5966035, 218.5528484275, 108.80088168578752, 146.40933354014618, 296.1278301696806, 622.6825063715801, 277.05724462819256,
+				129.85780402274509, 160.44231762170585, 332.6297619891003, 731.1310014393805, 347.5236156352407, 171.0732815734304, 198.97790946766696, 354.7534728516127,
+				684.4128433495046, 317.6539210971204, 174.2573577172455, 202.76945067631397, 358.165859939395, 687.4839917285085, 320.4179546382241, 176.74498790423877,
+				205.00831784460797, 360.1808403908596, 689.2974741348266, 322.0500888039104, 178.21390865335644},
+			nil,
+			[]float64{100, 220, 480, 210, 95, 130, 270, 610, 260, 120, 160, 330, 740, 320, 150},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			0.9,
+			15,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			prediction, err := holtwinters.PredictAdditiveDamped(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.phi, test.predictionLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, prediction, equateReference) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, prediction, equateReference))
+			}
+		})
+	}
+
+}
+
+func TestPredictMultiplicativeDamped(t *testing.T) {
+	// Expected values are generated by testdata/statsmodels_reference.py
+	equateReference := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description      string
+		expected         []float64
+		expectedErr      error
+		series           []float64
+		seasonLength     int
+		alpha            float64
+		beta             float64
+		gamma            float64
+		phi              float64
+		predictionLength int
+	}{
+		{
+			"Fail, alpha too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			1.5,
+			0.9,
+			0.9,
+			0.9,
+			3,
+		},
+		{
+			"Fail, phi too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 1.200000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			1.2,
+			3,
+		},
+		{
+			"Fail, phi zero",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 0.000000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0,
+			3,
+		},
+		{
+			"Fail, phi too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: -0.500000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			-0.5,
+			3,
+		},
+		{
+			"Success, 2 seasons data",
+			[]float64{1, 4.4552779684779695, 2.5570946160325736, 1.8333720647459328, 0.9637897527078059, 1.2776483451999114, 0.5671443697461893, 15.323322800697134,
+				1.602099430917248, 0.9670801735362246, 1.1832574776666793, 1.8425179442527848, 8.926827416358927, 1.2344392524666903, 0.832162391081434},
+			nil,
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+			5,
+			0.9,
+			0.9,
+			0.9,
+			0.8,
+			5,
+		},
+		{
+			"Success, 3 seasons data with trend, long horizon",
+			[]float64{100, 266.25353002504096, 535.6459413871854, 231.4683728005352, 103.17651625603811, 137.82749789159777, 295.4174116450861, 622.3612592883052,
+				266.8590654266733, 123.53169977091206, 165.4156679468628, 343.8327118658129, 750.905632436492, 325.7724378882682, 153.64213558867337, 180.7026893086662,
+				413.2026904025718, 898.1413619880642, 374.44886904235625, 167.79112425645016, 200.51850778636953, 452.91968433974597, 974.055821198389, 402.3577317870444,
+				178.84533581912186, 212.21955043926855, 476.37217208970793, 1018.8825502174936, 418.8376361491553, 185.3727372047639},
+			nil,
+			[]float64{100, 220, 480, 210, 95, 130, 270, 610, 260, 120, 160, 330, 740, 320, 150},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			0.9,
+			15,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			prediction, err := holtwinters.PredictMultiplicativeDamped(test.series, test.seasonLength, test.alpha, test.beta, test.gamma, test.phi, test.predictionLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, prediction, equateReference) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(test.expected, prediction, equateReference))
+			}
+		})
+	}
+
+}
+
+func TestPredictDampedNoDamping(t *testing.T) {
+	series := []float64{30, 21, 29, 31, 40, 48, 53, 47, 37, 39, 31, 29, 17, 9, 20, 24, 27, 35, 41, 38,
+		27, 31, 27, 26, 21, 13, 21, 18, 33, 35, 40, 36, 22, 24, 21, 20, 17, 14, 17, 19}
+
+	additive, _ := holtwinters.PredictAdditive(series, 12, 0.716, 0.029, 0.993, 24)
+	additiveDamped, _ := holtwinters.PredictAdditiveDamped(series, 12, 0.716, 0.029, 0.993, 1, 24)
+	if !cmp.Equal(additive, additiveDamped) {
+		t.Errorf("additive mismatch with phi of 1 (-want +got):\n%s", cmp.Diff(additive, additiveDamped))
+	}
+
+	multiplicative, _ := holtwinters.PredictMultiplicative(series, 12, 0.716, 0.029, 0.993, 24)
+	multiplicativeDamped, _ := holtwinters.PredictMultiplicativeDamped(series, 12, 0.716, 0.029, 0.993, 1, 24)
+	if !cmp.Equal(multiplicative, multiplicativeDamped) {
+		t.Errorf("multiplicative mismatch with phi of 1 (-want +got):\n%s", cmp.Diff(multiplicative, multiplicativeDamped))
+	}
+}
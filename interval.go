@@ -0,0 +1,149 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Interval is a forecast for a single step with a prediction interval around it
+type Interval struct {
+	// Point is the forecast value
+	Point float64
+	// Lower is the lower bound of the prediction interval
+	Lower float64
+	// Upper is the upper bound of the prediction interval
+	Upper float64
+}
+
+// PredictAdditiveIntervals takes in a seasonal historical series of data and produces a prediction of what the data will be in the future using
+// triple exponential smoothing using the additive method, with prediction intervals at the confidence level provided. Unlike PredictAdditive
+// only the predictions are returned, not the smoothed existing data.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the start of a season
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// predictionLength - Number of predictions to make, can't be negative
+// confidence - Confidence level of the prediction intervals, must be greater than 0 and less than 1, for example 0.95 for 95% intervals
+func PredictAdditiveIntervals(series []float64, seasonLength int, alpha float64, beta float64, gamma float64, predictionLength int, confidence float64) ([]Interval, error) {
+	return PredictIntervals(series, Config{
+		Method:           Additive,
+		SeasonLength:     seasonLength,
+		Alpha:            alpha,
+		Beta:             beta,
+		Gamma:            gamma,
+		PredictionLength: predictionLength,
+	}, confidence)
+}
+
+// ForecastIntervals makes predictions for the steps following the last observation the model was fitted to or updated
+// with, with prediction intervals at the confidence level provided, without changing the model's state. Only supported
+// for the additive method, a ParamError wrapping ErrMethod is returned otherwise. The intervals use the analytic
+// forecast variance for additive Holt-Winters, based on the variance of the one-step-ahead errors of the observations
+// smoothed so far and the smoothing coefficients, assuming the errors are normally distributed.
+// predictionLength - Number of predictions to make, can't be negative
+// confidence - Confidence level of the prediction intervals, must be greater than 0 and less than 1, for example 0.95
+// for 95% intervals
+func (m *Model) ForecastIntervals(predictionLength int, confidence float64) ([]Interval, error) {
+	if m.Observations == 0 {
+		return nil, errors.New("Model must be fitted before forecasting")
+	}
+	if m.Method != Additive {
+		return nil, &ParamError{Op: "prediction intervals", Name: "method", Value: m.Method, Range: "additive", Err: ErrMethod}
+	}
+	if predictionLength < 0 {
+		return nil, &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
+	}
+	err := validateConfidence(confidence)
+	if err != nil {
+		return nil, err
+	}
+	result := m.forecastIntervals(predictionLength, confidence)
+	err = validateFiniteIntervals(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// forecastIntervals makes predictions with prediction intervals, assumes the model is additive and fitted
+func (m *Model) forecastIntervals(predictionLength int, confidence float64) []Interval {
+	points := m.forecast(predictionLength)
+	variances := m.forecastVariances(predictionLength)
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	result := make([]Interval, predictionLength)
+	for step := 1; step <= predictionLength; step++ {
+		halfWidth := z * math.Sqrt(variances[step-1])
+		point := points[step-1]
+		result[step-1] = Interval{
+			Point: point,
+			Lower: point - halfWidth,
+			Upper: point + halfWidth,
+		}
+	}
+	return result
+}
+
+// forecastVariances returns the variance of the forecast errors for each step following the last observation, assumes
+// the model is additive and fitted
+func (m *Model) forecastVariances(predictionLength int) []float64 {
+	residualVariance := float64(0)
+	if m.residuals > 0 {
+		residualVariance = m.sse / float64(m.residuals)
+	}
+	// The variance h steps ahead is residualVariance * (1 + c_1^2 + ... + c_(h-1)^2), see Hyndman et al., Forecasting
+	// with Exponential Smoothing, 6.3. The coefficients of this package's smoothing equations convert to the state space
+	// form's as alpha, alpha*beta and gamma*(1-alpha) for each season length
+	result := make([]float64, predictionLength)
+	sumSquares := float64(0)
+	for step := 1; step <= predictionLength; step++ {
+		if step > 1 {
+			j := step - 1
+			c := m.Alpha + m.Alpha*m.Beta*dampedTrendMultiplier(m.Phi, j)
+			for k := range m.Seasonals {
+				if j%m.seasonLength(k) == 0 {
+					c += m.seasonGamma(k) * (1 - m.Alpha)
+				}
+			}
+			sumSquares += c * c
+		}
+		result[step-1] = residualVariance * (1 + sumSquares)
+	}
+	return result
+}
+
+// validateConfidence ensures the confidence level of prediction intervals is valid
+func validateConfidence(confidence float64) error {
+	if !(confidence > 0.0 && confidence < 1.0) {
+		return &ParamError{Op: "prediction", Name: "confidence", Value: confidence, Range: "greater than 0 and less than 1", Err: ErrConfidence}
+	}
+	return nil
+}
+
+// validateFiniteIntervals ensures the forecasts and bounds of every interval are finite
+func validateFiniteIntervals(intervals []Interval) error {
+	for i, interval := range intervals {
+		if missing(interval.Point) || missing(interval.Lower) || missing(interval.Upper) {
+			return fmt.Errorf("Result is not finite, interval %d is %f (%f, %f): %w", i, interval.Point, interval.Lower, interval.Upper, ErrNonFiniteResult)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestPredictAdditiveIntervals(t *testing.T) {
+	// Expected values are generated by testdata/statsmodels_reference.py
+	equateReference := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description      string
+		expected         []holtwinters.Interval
+		expectedErr      error
+		series           []float64
+		seasonLength     int
+		alpha            float64
+		beta             float64
+		gamma            float64
+		predictionLength int
+		confidence       float64
+	}{
+		{
+			"Fail, season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			[]float64{1, 2, 3, 2, 1},
+			1,
+			0.5,
+			0.3,
+			0.4,
+			3,
+			0.95,
+		},
+		{
+			"Fail, confidence too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "confidence", Value: 1.000000, Range: "greater than 0 and less than 1", Err: holtwinters.ErrConfidence},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			3,
+			1,
+		},
+		{
+			"Fail, confidence too low",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "confidence", Value: -0.500000, Range: "greater than 0 and less than 1", Err: holtwinters.ErrConfidence},
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			3,
+			-0.5,
+		},
+		{
+			"Success, no prediction",
+			[]holtwinters.Interval{},
+			nil,
+			[]float64{1, 2, 3, 2, 1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			0,
+			0.95,
+		},
+		{
+			"Success, 95% intervals",
+			[]holtwinters.Interval{
+				{1.157210750518133, 0.4864757981522154, 1.8279457028840507},
+				{2.202962555589156, 1.4029863385534536, 3.002938772624858},
+				{3.28710972132268, 2.323839983050501, 4.250379459594859},
+				{2.276115219075578, 1.121164942741335, 3.431065495409821},
+				{1.285208364723383, -0.08529207167135566, 2.655708801118122},
+				{1.3380083021232498, -0.3425140719861024, 3.018530676232602},
+				{2.3837601071942736, 0.45868068351333346, 4.308839530875214},
+			},
+			nil,
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+			5,
+			0.5,
+			0.3,
+			0.4,
+			7,
+			0.95,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			intervals, err := holtwinters.PredictAdditiveIntervals(test.series, test.seasonLength, test.alpha, test.beta, test.gamma,
+				test.predictionLength, test.confidence)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, intervals, equateReference) {
+				t.Errorf("intervals mismatch (-want +got):\n%s", cmp.Diff(test.expected, intervals, equateReference))
+			}
+		})
+	}
+}
+
+func TestModelForecastIntervals(t *testing.T) {
+	model := holtwinters.NewModel(holtwinters.Multiplicative, 12, 0.716, 0.029, 0.993)
+	_, err := model.ForecastIntervals(12, 0.95)
+	if err == nil || err.Error() != "Model must be fitted before forecasting" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	_, err = model.Fit(modelTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = model.ForecastIntervals(12, 0.95)
+	expectedErr := &holtwinters.ParamError{Op: "prediction intervals", Name: "method", Value: holtwinters.Multiplicative, Range: "additive", Err: holtwinters.ErrMethod}
+	if !cmp.Equal(error(expectedErr), err, equateErrors) {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+	}
+
+	model = holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+	_, err = model.Fit(modelTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forecast, err := model.Forecast(24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	narrow, err := model.ForecastIntervals(24, 0.8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wide, err := model.ForecastIntervals(24, 0.99)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range forecast {
+		if narrow[i].Point != forecast[i] || wide[i].Point != forecast[i] {
+			t.Errorf("step %d point mismatch, want %f, got %f and %f", i+1, forecast[i], narrow[i].Point, wide[i].Point)
+		}
+		if !(wide[i].Lower < narrow[i].Lower && narrow[i].Lower < forecast[i] && forecast[i] < narrow[i].Upper && narrow[i].Upper < wide[i].Upper) {
+			t.Errorf("step %d intervals not nested around point, 80%%: %+v, 99%%: %+v", i+1, narrow[i], wide[i])
+		}
+		if i > 0 && wide[i].Upper-wide[i].Lower < wide[i-1].Upper-wide[i-1].Lower {
+			t.Errorf("step %d interval narrower than step %d", i+1, i)
+		}
+	}
+}
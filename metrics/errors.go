@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidParameter is matched by every ParamError using errors.Is, regardless of which parameter was invalid
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// Sentinel errors for the parameters of the metrics, a ParamError for one of these parameters wraps the matching
+// sentinel so that it can be identified using errors.Is
+var (
+	// ErrLength is wrapped when the actual and forecast values are not the same length
+	ErrLength = errors.New("invalid length")
+	// ErrSeasonLength is wrapped when the season length is too short
+	ErrSeasonLength = errors.New("invalid season length")
+	// ErrTrainingLength is wrapped when the training series is not longer than the season length
+	ErrTrainingLength = errors.New("invalid training length")
+	// ErrNoValues is wrapped when there is no pair of actual and forecast values that are not missing
+	ErrNoValues = errors.New("no values")
+)
+
+// ErrUndefined is wrapped by the error returned when a metric is undefined for the values provided, such as MAPE when
+// every actual value is zero
+var ErrUndefined = errors.New("undefined metric")
+
+// ParamError is returned when a parameter is outside of the range of values allowed, it describes which parameter was
+// invalid, the value provided and the values allowed. Use errors.As to retrieve it, errors.Is with ErrInvalidParameter
+// to check for any invalid parameter, or errors.Is with one of the parameter sentinel errors, such as ErrLength, to
+// check for a specific parameter.
+type ParamError struct {
+	// Op is what the parameter was provided for, metric for every metric
+	Op string
+	// Name is the name of the parameter, such as season length
+	Name string
+	// Value is the invalid value provided for the parameter
+	Value interface{}
+	// Range describes the values allowed for the parameter, such as "at least 1"
+	Range string
+	// Err is the sentinel error for the parameter, such as ErrSeasonLength
+	Err error
+}
+
+// Error describes the invalid parameter, the values allowed and the value provided
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("Invalid parameter for %s; %s must be %s, is %v", e.Op, e.Name, e.Range, e.Value)
+}
+
+// Unwrap returns the sentinel error for the parameter
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInvalidParameter, which every ParamError matches
+func (e *ParamError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
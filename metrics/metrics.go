@@ -0,0 +1,220 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics provides measures of forecast accuracy, comparing actual values with forecasts or fitted values.
+//
+// Missing values are non-finite, NaN or ±Inf, as in the holtwinters package; any pair where either the actual or the
+// forecast value is missing is skipped. MAPE skips pairs where the actual value is zero, as the percentage error is undefined for them, and
+// sMAPE treats a pair where both values are zero as having no error. Percentage errors are returned as percentages,
+// for example 5 for 5%.
+//
+// Invalid parameters are returned as a ParamError, and an error wrapping ErrUndefined is returned when a metric is
+// undefined for the values provided.
+package metrics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Summary holds each of the accuracy metrics for a forecast
+type Summary struct {
+	// MAE is the mean absolute error
+	MAE float64
+	// RMSE is the root mean squared error
+	RMSE float64
+	// MAPE is the mean absolute percentage error, NaN if every actual value is zero or missing
+	MAPE float64
+	// SMAPE is the symmetric mean absolute percentage error
+	SMAPE float64
+	// MASE is the mean absolute scaled error, NaN if no training series was provided or its seasonal naive errors are
+	// all zero
+	MASE float64
+	// Bias is the mean error, forecast minus actual, positive if the forecast is too high on average
+	Bias float64
+}
+
+// Summarise calculates each of the accuracy metrics for the forecast provided. MAPE and MASE are NaN if they are
+// undefined for the values provided rather than returning an error.
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+// training - The series the model was fitted to, used to scale MASE, can be nil to skip MASE
+// seasonLength - The length of the training series' seasons, used for the seasonal naive baseline of MASE, 1 for a
+// non seasonal naive baseline
+func Summarise(actual []float64, forecast []float64, training []float64, seasonLength int) (*Summary, error) {
+	mae, err := MAE(actual, forecast)
+	if err != nil {
+		return nil, err
+	}
+	// Any remaining errors are only the metric being undefined, the inputs have already been validated by MAE
+	rmse, _ := RMSE(actual, forecast)
+	smape, _ := SMAPE(actual, forecast)
+	bias, _ := Bias(actual, forecast)
+	mape, err := MAPE(actual, forecast)
+	if err != nil {
+		mape = math.NaN()
+	}
+	mase := math.NaN()
+	if training != nil {
+		mase, err = MASE(actual, forecast, training, seasonLength)
+		if err != nil {
+			mase = math.NaN()
+		}
+	}
+	return &Summary{
+		MAE:   mae,
+		RMSE:  rmse,
+		MAPE:  mape,
+		SMAPE: smape,
+		MASE:  mase,
+		Bias:  bias,
+	}, nil
+}
+
+// MAE calculates the mean absolute error of the forecast, mean(|forecast - actual|).
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+func MAE(actual []float64, forecast []float64) (float64, error) {
+	return mean(actual, forecast, func(a float64, f float64) (float64, bool) {
+		return math.Abs(f - a), true
+	})
+}
+
+// RMSE calculates the root mean squared error of the forecast, sqrt(mean((forecast - actual)^2)).
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+func RMSE(actual []float64, forecast []float64) (float64, error) {
+	mse, err := mean(actual, forecast, func(a float64, f float64) (float64, bool) {
+		return (f - a) * (f - a), true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(mse), nil
+}
+
+// MAPE calculates the mean absolute percentage error of the forecast, 100 * mean(|forecast - actual| / |actual|).
+// Pairs where the actual value is zero are skipped, returns an error if every actual value is zero or missing.
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+func MAPE(actual []float64, forecast []float64) (float64, error) {
+	err := validatePairs(actual, forecast)
+	if err != nil {
+		return 0, err
+	}
+	mape, err := mean(actual, forecast, func(a float64, f float64) (float64, bool) {
+		if a == 0 {
+			return 0, false
+		}
+		return 100 * math.Abs(f-a) / math.Abs(a), true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("MAPE is undefined when every actual value is zero or missing: %w", ErrUndefined)
+	}
+	return mape, nil
+}
+
+// SMAPE calculates the symmetric mean absolute percentage error of the forecast,
+// 100 * mean(2 * |forecast - actual| / (|actual| + |forecast|)), between 0 and 200. Pairs where both values are zero
+// have no error.
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+func SMAPE(actual []float64, forecast []float64) (float64, error) {
+	return mean(actual, forecast, func(a float64, f float64) (float64, bool) {
+		denominator := math.Abs(a) + math.Abs(f)
+		if denominator == 0 {
+			return 0, true
+		}
+		return 100 * 2 * math.Abs(f-a) / denominator, true
+	})
+}
+
+// MASE calculates the mean absolute scaled error of the forecast, the MAE of the forecast divided by the MAE of a
+// seasonal naive forecast of the training series, which predicts each value as the value one season before it. A
+// MASE below 1 means the forecast is more accurate than the seasonal naive forecast was in sample.
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+// training - The series the model was fitted to, must be longer than the season length
+// seasonLength - The length of the training series' seasons, 1 for a non seasonal naive baseline
+func MASE(actual []float64, forecast []float64, training []float64, seasonLength int) (float64, error) {
+	if seasonLength < 1 {
+		return 0, &ParamError{Op: "metric", Name: "season length", Value: seasonLength, Range: "at least 1", Err: ErrSeasonLength}
+	}
+	if len(training) <= seasonLength {
+		return 0, &ParamError{Op: "metric", Name: "training length", Value: len(training), Range: fmt.Sprintf("greater than the season length %d", seasonLength), Err: ErrTrainingLength}
+	}
+	mae, err := MAE(actual, forecast)
+	if err != nil {
+		return 0, err
+	}
+	scale, err := mean(training[seasonLength:], training[:len(training)-seasonLength], func(a float64, f float64) (float64, bool) {
+		return math.Abs(f - a), true
+	})
+	if err != nil || scale == 0 {
+		return 0, fmt.Errorf("MASE is undefined when the seasonal naive errors of the training series are all zero or missing: %w", ErrUndefined)
+	}
+	return mae / scale, nil
+}
+
+// Bias calculates the mean error of the forecast, mean(forecast - actual), positive if the forecast is too high on
+// average.
+// actual - The actual values
+// forecast - The forecast or fitted values, must be the same length as actual
+func Bias(actual []float64, forecast []float64) (float64, error) {
+	return mean(actual, forecast, func(a float64, f float64) (float64, bool) {
+		return f - a, true
+	})
+}
+
+// mean calculates the mean of the error function over each pair of values where neither is missing, the error
+// function returns false to skip a pair. Returns an error if there are no pairs to take the mean of
+func mean(actual []float64, forecast []float64, errorFunc func(a float64, f float64) (float64, bool)) (float64, error) {
+	err := validatePairs(actual, forecast)
+	if err != nil {
+		return 0, err
+	}
+	sum := float64(0)
+	count := 0
+	for i, a := range actual {
+		f := forecast[i]
+		if missing(a) || missing(f) {
+			continue
+		}
+		val, ok := errorFunc(a, f)
+		if !ok {
+			continue
+		}
+		sum += val
+		count++
+	}
+	if count == 0 {
+		return 0, &ParamError{Op: "metric", Name: "pairs of actual and forecast values that are not missing", Value: 0, Range: "at least 1", Err: ErrNoValues}
+	}
+	return sum / float64(count), nil
+}
+
+// missing returns true if the value is a missing value, which is any non-finite value, NaN or ±Inf
+func missing(val float64) bool {
+	return math.IsNaN(val) || math.IsInf(val, 0)
+}
+
+// validatePairs ensures the actual and forecast values can be compared
+func validatePairs(actual []float64, forecast []float64) error {
+	if len(actual) != len(forecast) {
+		return &ParamError{Op: "metric", Name: "forecast length", Value: len(forecast), Range: fmt.Sprintf("the actual length %d", len(actual)), Err: ErrLength}
+	}
+	return nil
+}
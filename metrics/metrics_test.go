@@ -0,0 +1,353 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters/metrics"
+)
+
+// equateErrors compares the errors returned in the table driven tests. A ParamError is compared by its fields, with the
+// value compared by type as well as value and the sentinel by identity, so that the wrong sentinel or parameter is
+// caught rather than only a message that reads the same. Any other error is compared by its message and the sentinel
+// error it wraps, such as ErrUndefined.
+var equateErrors = cmp.Comparer(func(x, y error) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	xParam, xIsParam := x.(*metrics.ParamError)
+	yParam, yIsParam := y.(*metrics.ParamError)
+	if xIsParam || yIsParam {
+		return xIsParam && yIsParam && xParam.Op == yParam.Op && xParam.Name == yParam.Name &&
+			xParam.Range == yParam.Range && xParam.Err == yParam.Err && cmp.Equal(xParam.Value, yParam.Value)
+	}
+	return x.Error() == y.Error() && errors.Unwrap(x) == errors.Unwrap(y)
+})
+
+var (
+	testActual   = []float64{1, 2, 4, math.NaN(), 0}
+	testForecast = []float64{2, 2, 3, 5, 1}
+	testTraining = []float64{1, 3, 3, 5, 5, 7}
+)
+
+func TestMetrics(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+
+	var tests = []struct {
+		description string
+		expected    float64
+		expectedErr error
+		metric      func(actual []float64, forecast []float64) (float64, error)
+		actual      []float64
+		forecast    []float64
+	}{
+		{
+			"MAE, fail, mismatched lengths",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "forecast length", Value: 1, Range: "the actual length 2", Err: metrics.ErrLength},
+			metrics.MAE,
+			[]float64{1, 2},
+			[]float64{1},
+		},
+		{
+			"MAE, fail, no values",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "pairs of actual and forecast values that are not missing", Value: 0, Range: "at least 1", Err: metrics.ErrNoValues},
+			metrics.MAE,
+			[]float64{},
+			[]float64{},
+		},
+		{
+			"MAE, fail, all missing",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "pairs of actual and forecast values that are not missing", Value: 0, Range: "at least 1", Err: metrics.ErrNoValues},
+			metrics.MAE,
+			[]float64{math.NaN(), 1},
+			[]float64{1, math.NaN()},
+		},
+		{
+			"MAE, success",
+			0.75,
+			nil,
+			metrics.MAE,
+			testActual,
+			testForecast,
+		},
+		{
+			"MAE, success, infinite values skipped",
+			0.75,
+			nil,
+			metrics.MAE,
+			append(append([]float64{}, testActual...), math.Inf(1), 3),
+			append(append([]float64{}, testForecast...), 2, math.Inf(-1)),
+		},
+		{
+			"RMSE, fail, mismatched lengths",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "forecast length", Value: 1, Range: "the actual length 2", Err: metrics.ErrLength},
+			metrics.RMSE,
+			[]float64{1, 2},
+			[]float64{1},
+		},
+		{
+			"RMSE, success",
+			math.Sqrt(0.75),
+			nil,
+			metrics.RMSE,
+			testActual,
+			testForecast,
+		},
+		{
+			"MAPE, fail, mismatched lengths",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "forecast length", Value: 1, Range: "the actual length 2", Err: metrics.ErrLength},
+			metrics.MAPE,
+			[]float64{1, 2},
+			[]float64{1},
+		},
+		{
+			"MAPE, fail, all zero",
+			0,
+			fmt.Errorf("MAPE is undefined when every actual value is zero or missing: %w", metrics.ErrUndefined),
+			metrics.MAPE,
+			[]float64{0, 0, math.NaN()},
+			[]float64{1, 2, 3},
+		},
+		{
+			"MAPE, success, zeros skipped",
+			125.0 / 3,
+			nil,
+			metrics.MAPE,
+			testActual,
+			testForecast,
+		},
+		{
+			"SMAPE, fail, all missing",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "pairs of actual and forecast values that are not missing", Value: 0, Range: "at least 1", Err: metrics.ErrNoValues},
+			metrics.SMAPE,
+			[]float64{math.NaN()},
+			[]float64{1},
+		},
+		{
+			"SMAPE, success, both zero has no error",
+			0,
+			nil,
+			metrics.SMAPE,
+			[]float64{0, 1},
+			[]float64{0, 1},
+		},
+		{
+			"SMAPE, success",
+			73.80952380952381,
+			nil,
+			metrics.SMAPE,
+			testActual,
+			testForecast,
+		},
+		{
+			"Bias, success",
+			0.25,
+			nil,
+			metrics.Bias,
+			testActual,
+			testForecast,
+		},
+		{
+			"Bias, success, forecast too low",
+			-1.5,
+			nil,
+			metrics.Bias,
+			[]float64{3, 4},
+			[]float64{2, 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := test.metric(test.actual, test.forecast)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, result, equateApprox) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox))
+			}
+		})
+	}
+}
+
+func TestMASE(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+
+	var tests = []struct {
+		description  string
+		expected     float64
+		expectedErr  error
+		actual       []float64
+		forecast     []float64
+		training     []float64
+		seasonLength int
+	}{
+		{
+			"Fail, season length too short",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "season length", Value: 0, Range: "at least 1", Err: metrics.ErrSeasonLength},
+			testActual,
+			testForecast,
+			testTraining,
+			0,
+		},
+		{
+			"Fail, training series too short",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "training length", Value: 6, Range: "greater than the season length 6", Err: metrics.ErrTrainingLength},
+			testActual,
+			testForecast,
+			testTraining,
+			6,
+		},
+		{
+			"Fail, mismatched lengths",
+			0,
+			&metrics.ParamError{Op: "metric", Name: "forecast length", Value: 1, Range: "the actual length 5", Err: metrics.ErrLength},
+			testActual,
+			[]float64{1},
+			testTraining,
+			2,
+		},
+		{
+			"Fail, constant seasonal training series",
+			0,
+			fmt.Errorf("MASE is undefined when the seasonal naive errors of the training series are all zero or missing: %w", metrics.ErrUndefined),
+			testActual,
+			testForecast,
+			[]float64{1, 2, 1, 2, 1, 2},
+			2,
+		},
+		{
+			"Success, seasonal",
+			0.375,
+			nil,
+			testActual,
+			testForecast,
+			testTraining,
+			2,
+		},
+		{
+			"Success, non seasonal with missing training values",
+			0.75,
+			nil,
+			testActual,
+			testForecast,
+			[]float64{1, 2, math.NaN(), 4, 5},
+			1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := metrics.MASE(test.actual, test.forecast, test.training, test.seasonLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, result, equateApprox) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox))
+			}
+		})
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+	equateNaNs := cmpopts.EquateNaNs()
+
+	var tests = []struct {
+		description  string
+		expected     *metrics.Summary
+		expectedErr  error
+		actual       []float64
+		forecast     []float64
+		training     []float64
+		seasonLength int
+	}{
+		{
+			"Fail, mismatched lengths",
+			nil,
+			&metrics.ParamError{Op: "metric", Name: "forecast length", Value: 1, Range: "the actual length 5", Err: metrics.ErrLength},
+			testActual,
+			[]float64{1},
+			testTraining,
+			2,
+		},
+		{
+			"Success",
+			&metrics.Summary{
+				MAE:   0.75,
+				RMSE:  math.Sqrt(0.75),
+				MAPE:  125.0 / 3,
+				SMAPE: 73.80952380952381,
+				MASE:  0.375,
+				Bias:  0.25,
+			},
+			nil,
+			testActual,
+			testForecast,
+			testTraining,
+			2,
+		},
+		{
+			"Success, undefined MAPE and no training series",
+			&metrics.Summary{
+				MAE:   1.5,
+				RMSE:  math.Sqrt(2.5),
+				MAPE:  math.NaN(),
+				SMAPE: 200,
+				MASE:  math.NaN(),
+				Bias:  1.5,
+			},
+			nil,
+			[]float64{0, 0},
+			[]float64{1, 2},
+			nil,
+			0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			summary, err := metrics.Summarise(test.actual, test.forecast, test.training, test.seasonLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, summary, equateApprox, equateNaNs) {
+				t.Errorf("summary mismatch (-want +got):\n%s", cmp.Diff(test.expected, summary, equateApprox, equateNaNs))
+			}
+		})
+	}
+}
@@ -0,0 +1,492 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Method is the way the seasonal component is combined with the level and trend
+type Method int
+
+const (
+	// Additive method, the seasonal component is added to the level and trend, suited to seasonal variations that are
+	// roughly constant through the series
+	Additive Method = iota
+	// Multiplicative method, the level and trend are multiplied by the seasonal component, suited to seasonal variations
+	// that change proportionally to the level of the series
+	Multiplicative
+)
+
+// String returns the name of the method
+func (method Method) String() string {
+	switch method {
+	case Additive:
+		return "additive"
+	case Multiplicative:
+		return "multiplicative"
+	}
+	return fmt.Sprintf("Method(%d)", int(method))
+}
+
+// Model is a Holt-Winters model that holds its fitted state, allowing it to be fitted to a series once and then used to
+// make forecasts and take in new observations without smoothing the entire history again.
+// A Model should be created using NewModel or NewDampedModel and then fitted using Fit before it is used. By default
+// the first observation is assumed to be at the start of a season, Phase can be set before fitting if it is not. For
+// series with more than one seasonal cycle AdditionalSeasonLengths and AdditionalGammas can be set before fitting to
+// use Taylor's multiple seasonal Holt-Winters, with a seasonal component for each cycle.
+type Model struct {
+	// Method is how the seasonal component is combined with the level and trend
+	Method Method
+	// SeasonLength is the length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the
+	// series when fitting
+	SeasonLength int
+	// Alpha is the exponential smoothing coefficient for level, must be between 0 and 1
+	Alpha float64
+	// Beta is the exponential smoothing coefficient for trend, must be between 0 and 1
+	Beta float64
+	// Gamma is the exponential smoothing coefficient for seasonality, must be between 0 and 1
+	Gamma float64
+	// AdditionalSeasonLengths are the lengths of any further seasonal cycles in the data alongside SeasonLength, such
+	// as a weekly cycle alongside a daily one, each must be at least 2
+	AdditionalSeasonLengths []int
+	// AdditionalGammas are the exponential smoothing coefficients for the seasonal components of the additional season
+	// lengths, in the same order, each must be between 0 and 1
+	AdditionalGammas []float64
+	// Phi is the damping coefficient for trend, must be greater than 0 and at most 1, 1 applies no damping
+	Phi float64
+	// Level is the current smoothed level
+	Level float64
+	// Trend is the current smoothed trend
+	Trend float64
+	// Phase is the position in the season of the first observation the model is fitted to, for series that do not
+	// start at the beginning of a season, must be at least 0 and less than the season length. With additional season
+	// lengths it is the position in the longest season, and the position in each shorter season is the phase modulo
+	// its length
+	Phase int
+	// NonFinite is how non-finite observations, NaN and ±Inf, are handled when fitting and updating, by default they
+	// are skipped as missing observations
+	NonFinite NonFinitePolicy
+	// Seasonals are the current seasonal components, a vector for each season length, SeasonLength followed by each of
+	// AdditionalSeasonLengths, holding a component for each position in that season
+	Seasonals [][]float64
+	// Observations is the number of observations the model has been fitted to and updated with, 0 if the model has
+	// not been fitted
+	Observations int
+
+	// sse is the sum of the squared one-step-ahead errors of the observations smoothed so far
+	sse float64
+	// residuals is the number of one-step-ahead errors summed in sse
+	residuals int
+}
+
+// NewModel creates a new unfitted model with no trend damping
+// method - The method used to combine the seasonal component with the level and trend
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series when fitting
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+func NewModel(method Method, seasonLength int, alpha float64, beta float64, gamma float64) *Model {
+	return NewDampedModel(method, seasonLength, alpha, beta, gamma, 1)
+}
+
+// NewDampedModel creates a new unfitted model with a damped trend
+// method - The method used to combine the seasonal component with the level and trend
+// seasonLength - The length of the data's seasons, must be at least 2, or AutoSeasonLength to detect it from the series when fitting
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gamma - Exponential smoothing coefficient for seasonality, must be between 0 and 1
+// phi - Damping coefficient for trend, must be greater than 0 and at most 1, 1 applies no damping
+func NewDampedModel(method Method, seasonLength int, alpha float64, beta float64, gamma float64, phi float64) *Model {
+	return &Model{
+		Method:       method,
+		SeasonLength: seasonLength,
+		Alpha:        alpha,
+		Beta:         beta,
+		Gamma:        gamma,
+		Phi:          phi,
+	}
+}
+
+// Fit initialises the model's level, trend and seasonal components from the series provided and then smooths the
+// rest of the series, discarding any previously fitted state. Returns the smoothed series. If the parameters or series
+// are invalid, or the smoothed series, the model's state or its forecasts for the next season would be non-finite, an
+// error is returned and the model's state is left unchanged.
+// series - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// the first value should be at the position in the season given by the model's Phase
+func (m *Model) Fit(series []float64) ([]float64, error) {
+	// Keep the state from before fitting to restore if fitting fails, so the model is still usable
+	previous := m.copy()
+	result, err := m.fitFinite(series)
+	if err != nil {
+		*m = previous
+		return nil, err
+	}
+	return result, nil
+}
+
+// fitFinite validates the model and the series, fits the model to it and ensures the result and the model's state are
+// finite
+func (m *Model) fitFinite(series []float64) ([]float64, error) {
+	series, err := m.prepare(series)
+	if err != nil {
+		return nil, err
+	}
+	result := m.fit(series)
+	err = m.validateFiniteState(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Forecast makes predictions for the steps following the last observation the model was fitted to or updated with,
+// without changing the model's state.
+// predictionLength - Number of predictions to make, can't be negative
+func (m *Model) Forecast(predictionLength int) ([]float64, error) {
+	if m.Observations == 0 {
+		return nil, errors.New("Model must be fitted before forecasting")
+	}
+	if predictionLength < 0 {
+		return nil, &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
+	}
+	result := m.forecast(predictionLength)
+	err := validateFinite(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Update smooths a single new observation that follows the last observation the model was fitted to or updated with,
+// updating the model's level, trend and seasonal components. Returns the smoothed value for the observation. If the
+// observation would make the model's state or its forecasts for the next season non-finite an error wrapping
+// ErrNonFiniteResult is returned and the model's state is left unchanged.
+// observation - The new observation, NaN if the observation is missing, must be finite if the model's NonFinite policy
+// is RejectNonFinite
+func (m *Model) Update(observation float64) (float64, error) {
+	if m.Observations == 0 {
+		return 0, errors.New("Model must be fitted before updating")
+	}
+	if m.NonFinite == RejectNonFinite && missing(observation) {
+		return 0, &ParamError{Op: "prediction", Name: "observation", Value: observation, Range: "finite", Err: ErrSeriesValue}
+	}
+	// Keep the state from before the update to restore if the update is not finite, so the model is still usable
+	previous := m.copy()
+	result := m.update(observation)
+	err := m.validateFiniteState([]float64{result})
+	if err != nil {
+		*m = previous
+		return 0, err
+	}
+	return result, nil
+}
+
+// validateFiniteState ensures the results provided, and the model's level, trend and forecasts for the next season,
+// are finite
+func (m *Model) validateFiniteState(results []float64) error {
+	values := append(append([]float64{}, results...), m.Level, m.Trend)
+	return validateFinite(append(values, m.forecast(m.longestSeasonLength())...))
+}
+
+// prepare detects the model's season length from the series if it is AutoSeasonLength, ensures the model's
+// parameters and the series it is being fitted to are valid, and then handles the non-finite observations in the
+// series using the model's NonFinite policy, returning the series to fit the model to
+func (m *Model) prepare(series []float64) ([]float64, error) {
+	seasonLength, err := resolveSeasonLength(series, m.SeasonLength)
+	if err != nil {
+		return nil, err
+	}
+	m.SeasonLength = seasonLength
+	err = m.validate(series)
+	if err != nil {
+		return nil, err
+	}
+	return applyNonFinitePolicy(series, m.NonFinite)
+}
+
+// validate ensures the model's parameters and the series it is being fitted to are valid
+func (m *Model) validate(series []float64) error {
+	err := m.validateParams()
+	if err != nil {
+		return err
+	}
+	return validateSeriesLength(series, m.longestSeasonLength())
+}
+
+// validateParams ensures the model's parameters are valid
+func (m *Model) validateParams() error {
+	if m.Method != Additive && m.Method != Multiplicative {
+		return &ParamError{Op: "prediction", Name: "method", Value: int(m.Method), Range: "additive or multiplicative", Err: ErrMethod}
+	}
+	if m.SeasonLength <= 1 {
+		return &ParamError{Op: "prediction", Name: "season length", Value: m.SeasonLength, Range: "at least 2", Err: ErrSeasonLength}
+	}
+	if len(m.AdditionalGammas) != len(m.AdditionalSeasonLengths) {
+		return &ParamError{Op: "prediction", Name: "number of additional gammas", Value: len(m.AdditionalGammas), Range: fmt.Sprintf("the number of additional season lengths %d", len(m.AdditionalSeasonLengths)), Err: ErrGamma}
+	}
+	for _, seasonLength := range m.AdditionalSeasonLengths {
+		if seasonLength <= 1 {
+			return &ParamError{Op: "prediction", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
+		}
+	}
+	longest := m.longestSeasonLength()
+	if m.Phase < 0 || m.Phase >= longest {
+		return &ParamError{Op: "prediction", Name: "phase", Value: m.Phase, Range: fmt.Sprintf("at least 0 and less than the season length %d", longest), Err: ErrPhase}
+	}
+	if m.NonFinite < SkipNonFinite || m.NonFinite > ImputeNonFinite {
+		return &ParamError{Op: "prediction", Name: "non-finite policy", Value: int(m.NonFinite), Range: "skip, reject or impute", Err: ErrNonFinitePolicy}
+	}
+	err := validateSmoothingParams(m.Alpha, m.Beta, m.Gamma)
+	if err != nil {
+		return err
+	}
+	for _, gamma := range m.AdditionalGammas {
+		err = validateSmoothingParams(m.Alpha, m.Beta, gamma)
+		if err != nil {
+			return err
+		}
+	}
+	return validateDampingParam(m.Phi)
+}
+
+// initialise sets up the initial state from the series, with the first value of the series as the only observation,
+// assumes the model and the series have been validated
+func (m *Model) initialise(series []float64) {
+	m.Level = initialLevel(series)
+	m.Trend = initialTrend(series, m.longestSeasonLength())
+
+	// The seasonal components are calculated from the shortest season length to the longest, each from the series with
+	// the shorter seasonal components already removed, so that each captures only the variation not explained by the
+	// shorter ones
+	m.Seasonals = make([][]float64, len(m.AdditionalSeasonLengths)+1)
+	order := make([]int, len(m.Seasonals))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return m.seasonLength(order[a]) < m.seasonLength(order[b])
+	})
+	remaining := append([]float64{}, series...)
+	for _, k := range order {
+		seasonLength := m.seasonLength(k)
+		var seasonals []float64
+		if m.Method == Multiplicative {
+			seasonals = initialSeasonalComponentsMultiplicative(remaining, seasonLength)
+		} else {
+			seasonals = initialSeasonalComponentsAdditive(remaining, seasonLength)
+		}
+		for i := range remaining {
+			if m.Method == Multiplicative {
+				remaining[i] /= seasonals[i%seasonLength]
+			} else {
+				remaining[i] -= seasonals[i%seasonLength]
+			}
+		}
+		// Seasonal components are calculated relative to the start of the series, align them to the positions in the
+		// season
+		m.Seasonals[k] = make([]float64, seasonLength)
+		for i, seasonal := range seasonals {
+			m.Seasonals[k][m.seasonPosition(k, i)] = seasonal
+		}
+	}
+	m.Observations = 1
+	m.sse = 0
+	m.residuals = 0
+}
+
+// fit sets up the initial state from the series and smooths the rest of the series, assumes the model and the series
+// have been validated
+func (m *Model) fit(series []float64) []float64 {
+	m.initialise(series)
+
+	result := make([]float64, 1, len(series))
+	result[0] = m.Level
+	for _, val := range series[1:] {
+		result = append(result, m.update(val))
+	}
+	return result
+}
+
+// update applies the smoothing equations for a single observation, returning the smoothed value. A missing (non-finite)
+// observation does not update the state, instead the level is moved forward by the trend to the next step, as if the
+// observation had been the same as the forecast for it, and the forecast is returned as the smoothed value
+func (m *Model) update(val float64) float64 {
+	if missing(val) {
+		forecast := m.oneStepForecast()
+		m.Level = m.Level + m.Phi*m.Trend
+		m.Trend = m.Phi * m.Trend
+		m.Observations++
+		return forecast
+	}
+
+	i := m.Observations
+	seasonal := m.seasonal(i)
+	residual := val - m.oneStepForecast()
+	m.sse += residual * residual
+	m.residuals++
+	m.Observations++
+	lastLevel := m.Level
+	lastBase := m.Level + m.Phi*m.Trend
+	if m.Method == Multiplicative {
+		m.Level = m.Alpha*(val/seasonal) + (1-m.Alpha)*lastBase
+	} else {
+		m.Level = m.Alpha*(val-seasonal) + (1-m.Alpha)*lastBase
+	}
+	m.Trend = m.Beta*(m.Level-lastLevel) + (1-m.Beta)*m.Phi*m.Trend
+	// Each seasonal component is smoothed against the observation with the other seasonal components from before the
+	// observation removed, and with the updated level removed for the additive method, or the level and trend forecast
+	// for the observation for the multiplicative method
+	for k, seasonals := range m.Seasonals {
+		slot := m.seasonPosition(k, i)
+		gamma := m.seasonGamma(k)
+		if m.Method == Multiplicative {
+			others := seasonal / seasonals[slot]
+			seasonals[slot] = gamma*(val/(lastBase*others)) + (1-gamma)*seasonals[slot]
+		} else {
+			others := seasonal - seasonals[slot]
+			seasonals[slot] = gamma*(val-m.Level-others) + (1-gamma)*seasonals[slot]
+		}
+	}
+	if m.Method == Multiplicative {
+		return (m.Level + m.Phi*m.Trend) * m.seasonal(i)
+	}
+	return m.Level + m.Phi*m.Trend + m.seasonal(i)
+}
+
+// oneStepForecast returns the prediction for the step following the last observation
+func (m *Model) oneStepForecast() float64 {
+	seasonal := m.seasonal(m.Observations)
+	if m.Method == Multiplicative {
+		return (m.Level + m.Phi*m.Trend) * seasonal
+	}
+	return (m.Level + m.Phi*m.Trend) + seasonal
+}
+
+// fitSSE fits the model to the series, returning the sum of the squared one-step-ahead errors, assumes the model and
+// the series have been validated
+func (m *Model) fitSSE(series []float64) float64 {
+	m.initialise(series)
+	for _, val := range series[1:] {
+		m.update(val)
+	}
+	return m.sse
+}
+
+// ForecastSlots returns the position in the season that each of the predictions made by Forecast falls in, where 0 is
+// the start of a season.
+// predictionLength - Number of predictions to make, can't be negative
+func (m *Model) ForecastSlots(predictionLength int) ([]int, error) {
+	if m.Observations == 0 {
+		return nil, errors.New("Model must be fitted before forecasting")
+	}
+	if predictionLength < 0 {
+		return nil, &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
+	}
+	slots := make([]int, predictionLength)
+	for step := 1; step <= predictionLength; step++ {
+		slots[step-1] = m.seasonIndex(m.Observations + step - 1)
+	}
+	return slots, nil
+}
+
+// seasonIndex returns the position in the season of the observation at the index provided, counting from the first
+// observation the model was fitted to
+func (m *Model) seasonIndex(observation int) int {
+	return m.seasonPosition(0, observation)
+}
+
+// seasonPosition returns the position in the season of the seasonal components at index k of Seasonals of the
+// observation at the index provided, counting from the first observation the model was fitted to
+func (m *Model) seasonPosition(k int, observation int) int {
+	return (m.Phase + observation) % m.seasonLength(k)
+}
+
+// seasonLength returns the length of the season of the seasonal components at index k of Seasonals
+func (m *Model) seasonLength(k int) int {
+	if k == 0 {
+		return m.SeasonLength
+	}
+	return m.AdditionalSeasonLengths[k-1]
+}
+
+// seasonGamma returns the smoothing coefficient of the seasonal components at index k of Seasonals
+func (m *Model) seasonGamma(k int) float64 {
+	if k == 0 {
+		return m.Gamma
+	}
+	return m.AdditionalGammas[k-1]
+}
+
+// longestSeasonLength returns the longest of the model's season lengths
+func (m *Model) longestSeasonLength() int {
+	longest := m.SeasonLength
+	for _, seasonLength := range m.AdditionalSeasonLengths {
+		if seasonLength > longest {
+			longest = seasonLength
+		}
+	}
+	return longest
+}
+
+// seasonal returns the seasonal components for the observation at the index provided combined, added for the additive
+// method or multiplied for the multiplicative method
+func (m *Model) seasonal(observation int) float64 {
+	combined := m.Seasonals[0][m.seasonIndex(observation)]
+	for k := 1; k < len(m.Seasonals); k++ {
+		seasonal := m.Seasonals[k][m.seasonPosition(k, observation)]
+		if m.Method == Multiplicative {
+			combined *= seasonal
+		} else {
+			combined += seasonal
+		}
+	}
+	return combined
+}
+
+// copy returns a copy of the model that does not share its seasonal components' storage
+func (m *Model) copy() Model {
+	copied := *m
+	copied.Seasonals = copySeasonals(m.Seasonals)
+	return copied
+}
+
+// copySeasonals returns a copy of the seasonal components that does not share their storage
+func copySeasonals(seasonals [][]float64) [][]float64 {
+	copied := make([][]float64, len(seasonals))
+	for k := range seasonals {
+		copied[k] = append([]float64{}, seasonals[k]...)
+	}
+	return copied
+}
+
+// forecast makes predictions for the steps following the last observation
+func (m *Model) forecast(predictionLength int) []float64 {
+	result := make([]float64, predictionLength)
+	for step := 1; step <= predictionLength; step++ {
+		trend := dampedTrendMultiplier(m.Phi, step) * m.Trend
+		seasonal := m.seasonal(m.Observations + step - 1)
+		if m.Method == Multiplicative {
+			result[step-1] = (m.Level + trend) * seasonal
+		} else {
+			result[step-1] = (m.Level + trend) + seasonal
+		}
+	}
+	return result
+}
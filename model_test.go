@@ -0,0 +1,309 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+var modelTestSeries = []float64{30, 21, 29, 31, 40, 48, 53, 47, 37, 39, 31, 29, 17, 9, 20, 24, 27, 35, 41, 38,
+	27, 31, 27, 26, 21, 13, 21, 18, 33, 35, 40, 36, 22, 24, 21, 20, 17, 14, 17, 19,
+	26, 29, 40, 31, 20, 24, 18, 26, 17, 9, 17, 21, 28, 32, 46, 33, 23, 28, 22, 27,
+	18, 8, 17, 21, 31, 34, 44, 38, 31, 30, 26, 32}
+
+func TestModelFit(t *testing.T) {
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		model       *holtwinters.Model
+		series      []float64
+	}{
+		{
+			"Fail, unknown method",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "method", Value: 5, Range: "additive or multiplicative", Err: holtwinters.ErrMethod},
+			holtwinters.NewModel(holtwinters.Method(5), 5, 0.9, 0.9, 0.9),
+			[]float64{1, 2, 3, 2, 1},
+		},
+		{
+			"Fail, season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			holtwinters.NewModel(holtwinters.Additive, 1, 0.9, 0.9, 0.9),
+			[]float64{1, 2, 3, 2, 1},
+		},
+		{
+			"Fail, phi too high",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 1.200000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			holtwinters.NewDampedModel(holtwinters.Additive, 5, 0.9, 0.9, 0.9, 1.2),
+			[]float64{1, 2, 3, 2, 1},
+		},
+		{
+			"Fail, zero value model, phi not set",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: 0.000000, Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			&holtwinters.Model{SeasonLength: 5},
+			[]float64{1, 2, 3, 2, 1},
+		},
+		{
+			"Fail, data provided less than full season",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 3, Range: "at least the season length 5", Err: holtwinters.ErrSeriesLength},
+			holtwinters.NewModel(holtwinters.Multiplicative, 5, 0.9, 0.9, 0.9),
+			[]float64{1, 2, 3},
+		},
+		{
+			"Success, additive",
+			[]float64{1, 2.7064000000000004, 3.132456, 1.96677224, 0.9771183496000001, 1.1766870973840002, 1.7830314232813598, 3.2515613630131943,
+				2.1199062313456905, 1.0747739825249312},
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 5, 0.9, 0.9, 0.9),
+			[]float64{1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			smoothed, err := test.model.Fit(test.series)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, smoothed) {
+				t.Errorf("smoothed mismatch (-want +got):\n%s", cmp.Diff(test.expected, smoothed))
+			}
+		})
+	}
+}
+
+func TestModelForecast(t *testing.T) {
+	var tests = []struct {
+		description string
+		model       *holtwinters.Model
+		predict     func(series []float64, predictionLength int) ([]float64, error)
+	}{
+		{
+			"Additive",
+			holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993),
+			func(series []float64, predictionLength int) ([]float64, error) {
+				return holtwinters.PredictAdditive(series, 12, 0.716, 0.029, 0.993, predictionLength)
+			},
+		},
+		{
+			"Multiplicative",
+			holtwinters.NewModel(holtwinters.Multiplicative, 12, 0.716, 0.029, 0.993),
+			func(series []float64, predictionLength int) ([]float64, error) {
+				return holtwinters.PredictMultiplicative(series, 12, 0.716, 0.029, 0.993, predictionLength)
+			},
+		},
+		{
+			"Additive damped",
+			holtwinters.NewDampedModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993, 0.9),
+			func(series []float64, predictionLength int) ([]float64, error) {
+				return holtwinters.PredictAdditiveDamped(series, 12, 0.716, 0.029, 0.993, 0.9, predictionLength)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			expected, err := test.predict(modelTestSeries, 24)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			smoothed, err := test.model.Fit(modelTestSeries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			forecast, err := test.model.Forecast(24)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := append(smoothed, forecast...)
+			if !cmp.Equal(expected, got) {
+				t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(expected, got))
+			}
+		})
+	}
+}
+
+func TestModelUpdate(t *testing.T) {
+	// Initial state is calculated from the first 30 values only, the rest are smoothed one at a time using Update
+	expected := []float64{30, 20.37161405555556, 28.41589019752822, 30.41883507778698, 39.4860787322253, 47.52181099128986, 52.53250006944105, 46.51608493619412, 36.527877050736855,
+		38.523247172185485, 30.492291582393644, 28.480678252475933, 16.336961263544897, 8.259308268643569, 19.31339484422015, 23.37642601291697, 26.334801729190733,
+		34.33971520113971, 40.3712712582725, 37.43008981495625, 26.44426711777302, 30.482245908455997, 26.550746697445135, 25.58743846770067, 20.625898175195577,
+		12.589269036961394, 20.55467494268847, 17.447592378588418, 32.5970881897798, 34.54890944706423, 39.52282607717705, 35.53367580030403, 21.48635246427645,
+		23.455762908196714, 20.511532609936303, 19.556381517717103, 16.634856139261892, 13.728437189013757, 16.643419193802057, 18.624556174558155, 25.582805679456477,
+		28.53922814409444, 39.62306537938845, 30.570683610088665, 19.567427600807807, 23.58646659463855, 17.5769105261913, 25.76794920313182, 16.779301872908007,
+		8.74075282311263, 16.752261231671348, 20.787387217941294, 27.768019119462345, 31.74624366015079, 45.85435772056549, 32.77334340935128, 22.75471767626038,
+		27.779979318984868, 21.77822453531825, 26.85440056771467, 17.876046780558557, 7.81493316947177, 16.819176157966567, 20.846176903083272, 30.894976512922717,
+		33.877791444098904, 43.87075531398917, 37.933514339375996, 31.00504411699504, 29.93164337991729, 25.931962284265047, 32.00630960813884, 21.531867916859017,
+		14.061648123129364, 22.845309603489795, 26.051708374674963, 34.458196298493625, 38.42095239833535, 48.7369773458808, 40.46139022066721, 31.062797892690753,
+		33.141149359035516, 28.88641544353845, 32.16121869131975}
+
+	model := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+	got, err := model.Fit(modelTestSeries[:30])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, val := range modelTestSeries[30:] {
+		smoothed, err := model.Update(val)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, smoothed)
+	}
+	forecast, err := model.Forecast(12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = append(got, forecast...)
+
+	if !cmp.Equal(expected, got) {
+		t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(expected, got))
+	}
+	if model.Observations != len(modelTestSeries) {
+		t.Errorf("observations mismatch, want %d, got %d", len(modelTestSeries), model.Observations)
+	}
+}
+
+func TestModelNotFitted(t *testing.T) {
+	model := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+
+	_, err := model.Forecast(5)
+	if err == nil || err.Error() != "Model must be fitted before forecasting" {
+		t.Errorf("unexpected forecast error: %v", err)
+	}
+
+	_, err = model.Update(5)
+	if err == nil || err.Error() != "Model must be fitted before updating" {
+		t.Errorf("unexpected update error: %v", err)
+	}
+
+	_, err = model.Fit(modelTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = model.Forecast(-1)
+	expectedErr := &holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -1, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength}
+	if !cmp.Equal(error(expectedErr), err, equateErrors) {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+	}
+}
+
+func TestModelUpdateMissing(t *testing.T) {
+	model := holtwinters.NewDampedModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993, 0.9)
+	_, err := model.Fit(modelTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := model.Forecast(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seasonals := append([]float64{}, model.Seasonals[0]...)
+
+	// A missing observation should smooth to its forecast and leave the forecast for the following step unchanged
+	smoothed, err := model.Update(math.NaN())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if smoothed != expected[0] {
+		t.Errorf("smoothed mismatch, want %v, got %v", expected[0], smoothed)
+	}
+	forecast, err := model.Forecast(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(expected[1:], forecast, cmpopts.EquateApprox(0, 1e-12)) {
+		t.Errorf("forecast mismatch (-want +got):\n%s", cmp.Diff(expected[1:], forecast))
+	}
+	if !cmp.Equal(seasonals, model.Seasonals[0]) {
+		t.Errorf("seasonals changed by missing observation (-want +got):\n%s", cmp.Diff(seasonals, model.Seasonals[0]))
+	}
+	if model.Observations != len(modelTestSeries)+1 {
+		t.Errorf("observations mismatch, want %d, got %d", len(modelTestSeries)+1, model.Observations)
+	}
+}
+
+func TestModelPhase(t *testing.T) {
+	// The series starts 3 steps into the season, so the model with the phase set should hold the same seasonal
+	// components as one assuming the series starts at the beginning of a season, shifted to their positions in the season
+	series := modelTestSeries[3:]
+
+	unaligned := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+	unalignedSmoothed, err := unaligned.Fit(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aligned := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+	aligned.Phase = 3
+	alignedSmoothed, err := aligned.Fit(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(unalignedSmoothed, alignedSmoothed) {
+		t.Errorf("smoothed mismatch (-want +got):\n%s", cmp.Diff(unalignedSmoothed, alignedSmoothed))
+	}
+	for i := range aligned.Seasonals[0] {
+		if aligned.Seasonals[0][(i+3)%12] != unaligned.Seasonals[0][i] {
+			t.Errorf("seasonal %d not shifted by phase, want %v, got %v", i, unaligned.Seasonals[0][i], aligned.Seasonals[0][(i+3)%12])
+		}
+	}
+
+	unalignedForecast, err := unaligned.Forecast(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alignedForecast, err := aligned.Forecast(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(unalignedForecast, alignedForecast) {
+		t.Errorf("forecast mismatch (-want +got):\n%s", cmp.Diff(unalignedForecast, alignedForecast))
+	}
+
+	// 69 observations starting at position 3, so the last is at position 11 and forecasts start at the next season
+	slots, err := aligned.ForecastSlots(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal([]int{0, 1, 2, 3, 4, 5}, slots) {
+		t.Errorf("slots mismatch (-want +got):\n%s", cmp.Diff([]int{0, 1, 2, 3, 4, 5}, slots))
+	}
+
+	for _, phase := range []int{-1, 12} {
+		invalid := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+		invalid.Phase = phase
+		_, err = invalid.Fit(series)
+		expectedErr := &holtwinters.ParamError{Op: "prediction", Name: "phase", Value: phase, Range: "at least 0 and less than the season length 12", Err: holtwinters.ErrPhase}
+		if !cmp.Equal(error(expectedErr), err, equateErrors) {
+			t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import "fmt"
+
+// PredictAdditiveMultiSeasonal takes in a historical series of data with more than one seasonal cycle, such as a daily
+// and a weekly cycle, and produces a prediction of what the data will be in the future using the additive method of
+// Taylor's multiple seasonal Holt-Winters, which holds a seasonal component for each cycle. Existing data will also be
+// smoothed alongside predictions. Returns the entire dataset with the predictions appended to the end. With a single
+// season length this gives the same results as PredictAdditive.
+// series - Historical seasonal data, must be at least a full season of the longest season length, for optimal results
+// use at least two, the first value should be at the start of every season
+// seasonLengths - The length of each of the data's seasons, each must be at least 2
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gammas - Exponential smoothing coefficient for each seasonal component, in the same order as the season lengths,
+// each must be between 0 and 1
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+func PredictAdditiveMultiSeasonal(series []float64, seasonLengths []int, alpha float64, beta float64, gammas []float64, predictionLength int) ([]float64, error) {
+	return predictMultiSeasonal(Additive, series, seasonLengths, alpha, beta, gammas, predictionLength)
+}
+
+// PredictMultiplicativeMultiSeasonal takes in a historical series of data with more than one seasonal cycle, such as
+// a daily and a weekly cycle, and produces a prediction of what the data will be in the future using the
+// multiplicative method of Taylor's multiple seasonal Holt-Winters, which holds a seasonal component for each cycle.
+// Existing data will also be smoothed alongside predictions. Returns the entire dataset with the predictions appended
+// to the end. With a single season length this gives the same results as PredictMultiplicative.
+// series - Historical seasonal data, must be at least a full season of the longest season length, for optimal results
+// use at least two, the first value should be at the start of every season
+// seasonLengths - The length of each of the data's seasons, each must be at least 2
+// alpha - Exponential smoothing coefficient for level, must be between 0 and 1
+// beta - Exponential smoothing coefficient for trend, must be between 0 and 1
+// gammas - Exponential smoothing coefficient for each seasonal component, in the same order as the season lengths,
+// each must be between 0 and 1
+// predictionLength - Number of predictions to make, set to 0 to make no predictions and only smooth, can't be negative
+func PredictMultiplicativeMultiSeasonal(series []float64, seasonLengths []int, alpha float64, beta float64, gammas []float64, predictionLength int) ([]float64, error) {
+	return predictMultiSeasonal(Multiplicative, series, seasonLengths, alpha, beta, gammas, predictionLength)
+}
+
+// predictMultiSeasonal validates the parameters and then smooths the series and makes predictions using a model with
+// the first season length and gamma as its SeasonLength and Gamma, and the rest as its additional season lengths
+func predictMultiSeasonal(method Method, series []float64, seasonLengths []int, alpha float64, beta float64, gammas []float64, predictionLength int) ([]float64, error) {
+	err := validateMultiSeasonalParams(seasonLengths, gammas, predictionLength)
+	if err != nil {
+		return nil, err
+	}
+	model := NewModel(method, seasonLengths[0], alpha, beta, gammas[0])
+	model.AdditionalSeasonLengths = seasonLengths[1:]
+	model.AdditionalGammas = gammas[1:]
+	series, err = model.prepare(series)
+	if err != nil {
+		return nil, err
+	}
+	result := append(model.fit(series), model.forecast(predictionLength)...)
+	err = validateFinite(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateMultiSeasonalParams ensures the season lengths, gammas and prediction length provided for multiple
+// seasonality are valid, the remaining parameters and the series are validated by the model
+func validateMultiSeasonalParams(seasonLengths []int, gammas []float64, predictionLength int) error {
+	if len(seasonLengths) == 0 {
+		return &ParamError{Op: "prediction", Name: "number of season lengths", Value: 0, Range: "at least 1", Err: ErrSeasonLength}
+	}
+	if len(gammas) != len(seasonLengths) {
+		return &ParamError{Op: "prediction", Name: "number of gammas", Value: len(gammas), Range: fmt.Sprintf("the number of season lengths %d", len(seasonLengths)), Err: ErrGamma}
+	}
+	for _, seasonLength := range seasonLengths {
+		if seasonLength <= 1 {
+			return &ParamError{Op: "prediction", Name: "season length", Value: seasonLength, Range: "at least 2", Err: ErrSeasonLength}
+		}
+	}
+	if predictionLength < 0 {
+		return &ParamError{Op: "prediction", Name: "prediction length", Value: predictionLength, Range: "at least 0, cannot be negative", Err: ErrPredictionLength}
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+// multiSeasonalTestSeries returns a series with a trend and two seasonal cycles, of lengths 4 and 7
+func multiSeasonalTestSeries(length int) []float64 {
+	short := []float64{0, 6, -2, -4}
+	long := []float64{3, 1, -1, -3, -2, 0, 2}
+	series := make([]float64, length)
+	for i := range series {
+		series[i] = 50 + 0.1*float64(i) + short[i%4] + long[i%7]
+	}
+	return series
+}
+
+func TestPredictMultiSeasonal(t *testing.T) {
+
+	var tests = []struct {
+		description      string
+		expectedErr      error
+		series           []float64
+		seasonLengths    []int
+		alpha            float64
+		beta             float64
+		gammas           []float64
+		predictionLength int
+	}{
+		{
+			"Fail, no season lengths",
+			&holtwinters.ParamError{Op: "prediction", Name: "number of season lengths", Value: 0, Range: "at least 1", Err: holtwinters.ErrSeasonLength},
+			multiSeasonalTestSeries(28),
+			[]int{},
+			0.2,
+			0.05,
+			[]float64{},
+			7,
+		},
+		{
+			"Fail, gamma missing",
+			&holtwinters.ParamError{Op: "prediction", Name: "number of gammas", Value: 1, Range: "the number of season lengths 2", Err: holtwinters.ErrGamma},
+			multiSeasonalTestSeries(28),
+			[]int{4, 7},
+			0.2,
+			0.05,
+			[]float64{0.2},
+			7,
+		},
+		{
+			"Fail, season length too short",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			multiSeasonalTestSeries(28),
+			[]int{4, 1},
+			0.2,
+			0.05,
+			[]float64{0.2, 0.2},
+			7,
+		},
+		{
+			"Fail, negative prediction length",
+			&holtwinters.ParamError{Op: "prediction", Name: "prediction length", Value: -1, Range: "at least 0, cannot be negative", Err: holtwinters.ErrPredictionLength},
+			multiSeasonalTestSeries(28),
+			[]int{4, 7},
+			0.2,
+			0.05,
+			[]float64{0.2, 0.2},
+			-1,
+		},
+		{
+			"Fail, gamma too high",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			multiSeasonalTestSeries(28),
+			[]int{4, 7},
+			0.2,
+			0.05,
+			[]float64{0.2, 1.5},
+			7,
+		},
+		{
+			"Fail, less than a season of the longest season length",
+			&holtwinters.ParamError{Op: "prediction", Name: "series length", Value: 6, Range: "at least the season length 7", Err: holtwinters.ErrSeriesLength},
+			multiSeasonalTestSeries(6),
+			[]int{4, 7},
+			0.2,
+			0.05,
+			[]float64{0.2, 0.2},
+			7,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			for _, predict := range []func([]float64, []int, float64, float64, []float64, int) ([]float64, error){
+				holtwinters.PredictAdditiveMultiSeasonal,
+				holtwinters.PredictMultiplicativeMultiSeasonal,
+			} {
+				_, err := predict(test.series, test.seasonLengths, test.alpha, test.beta, test.gammas, test.predictionLength)
+				if !cmp.Equal(test.expectedErr, err, equateErrors) {
+					t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				}
+			}
+		})
+	}
+}
+
+func TestPredictMultiSeasonalSingleSeason(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+	series := append(append([]float64{}, modelTestSeries[:30]...), math.NaN())
+	series = append(series, modelTestSeries[31:]...)
+
+	additive, err := holtwinters.PredictAdditive(series, 12, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	additiveMulti, err := holtwinters.PredictAdditiveMultiSeasonal(series, []int{12}, 0.716, 0.029, []float64{0.993}, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(additive, additiveMulti, equateApprox) {
+		t.Errorf("additive mismatch (-want +got):\n%s", cmp.Diff(additive, additiveMulti, equateApprox))
+	}
+
+	multiplicative, err := holtwinters.PredictMultiplicative(series, 12, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplicativeMulti, err := holtwinters.PredictMultiplicativeMultiSeasonal(series, []int{12}, 0.716, 0.029, []float64{0.993}, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(multiplicative, multiplicativeMulti, equateApprox) {
+		t.Errorf("multiplicative mismatch (-want +got):\n%s", cmp.Diff(multiplicative, multiplicativeMulti, equateApprox))
+	}
+}
+
+func TestPredictMultiSeasonalTwoSeasons(t *testing.T) {
+	series := multiSeasonalTestSeries(112)
+	training, actual := series[:84], series[84:]
+	meanAbsoluteError := func(prediction []float64) float64 {
+		sum := float64(0)
+		for i, val := range actual {
+			sum += math.Abs(prediction[len(training)+i] - val)
+		}
+		return sum / float64(len(actual))
+	}
+
+	// Neither cycle alone can capture the pattern, as the season lengths are not multiples of each other
+	singleShort, err := holtwinters.PredictAdditive(training, 4, 0.2, 0.05, 0.2, len(actual))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	singleLong, err := holtwinters.PredictAdditive(training, 7, 0.2, 0.05, 0.2, len(actual))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	baseline := math.Min(meanAbsoluteError(singleShort), meanAbsoluteError(singleLong))
+
+	for _, predict := range []func([]float64, []int, float64, float64, []float64, int) ([]float64, error){
+		holtwinters.PredictAdditiveMultiSeasonal,
+		holtwinters.PredictMultiplicativeMultiSeasonal,
+	} {
+		prediction, err := predict(training, []int{7, 4}, 0.2, 0.05, []float64{0.2, 0.2}, len(actual))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(prediction) != len(series) {
+			t.Fatalf("prediction length mismatch, want %d, got %d", len(series), len(prediction))
+		}
+		if mae := meanAbsoluteError(prediction); mae > 0.5 || mae > baseline/4 {
+			t.Errorf("expected mean absolute error well below single season baseline %f, got %f", baseline, mae)
+		}
+	}
+}
+
+func TestModelMultiSeasonal(t *testing.T) {
+	// The series starts 5 steps into the longer season, and so 1 step into the shorter season
+	series := multiSeasonalTestSeries(117)[5:]
+	training, actual := series[:84], series[84:]
+
+	newModel := func(phase int) *holtwinters.Model {
+		model := holtwinters.NewDampedModel(holtwinters.Additive, 4, 0.2, 0.05, 0.2, 0.98)
+		model.AdditionalSeasonLengths = []int{7}
+		model.AdditionalGammas = []float64{0.2}
+		model.Phase = phase
+		return model
+	}
+
+	// The model with the phase set should give the same results as one assuming the series starts at the beginning of
+	// every season, as its seasonal components are only shifted to their positions in each season
+	unaligned := newModel(0)
+	unalignedSmoothed, err := unaligned.Fit(training)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model := newModel(5)
+	smoothed, err := model.Fit(training)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(unalignedSmoothed, smoothed) {
+		t.Errorf("smoothed mismatch (-want +got):\n%s", cmp.Diff(unalignedSmoothed, smoothed))
+	}
+	for i := range model.Seasonals[1] {
+		if model.Seasonals[1][(i+5)%7] != unaligned.Seasonals[1][i] {
+			t.Errorf("seasonal %d not shifted by phase, want %v, got %v", i, unaligned.Seasonals[1][i], model.Seasonals[1][(i+5)%7])
+		}
+	}
+
+	forecast, err := model.Forecast(len(actual))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sum := float64(0)
+	for i, val := range actual {
+		sum += math.Abs(forecast[i] - val)
+	}
+	// The damped trend falls behind the series' trend over the horizon, so the error is higher than undamped
+	if mae := sum / float64(len(actual)); mae > 1 {
+		t.Errorf("expected mean absolute error of at most 1, got %f", mae)
+	}
+
+	// A model restored from JSON should continue from the same state
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored := &holtwinters.Model{}
+	err = json.Unmarshal(encoded, restored)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, val := range actual {
+		expected, err := model.Update(val)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		smoothed, err := restored.Update(val)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected != smoothed {
+			t.Errorf("smoothed value mismatch, want %v, got %v", expected, smoothed)
+		}
+	}
+}
+
+func TestModelMultiSeasonalParams(t *testing.T) {
+
+	var tests = []struct {
+		description             string
+		expectedErr             error
+		additionalSeasonLengths []int
+		additionalGammas        []float64
+		phase                   int
+	}{
+		{
+			"Fail, additional gamma missing",
+			&holtwinters.ParamError{Op: "prediction", Name: "number of additional gammas", Value: 0, Range: "the number of additional season lengths 1", Err: holtwinters.ErrGamma},
+			[]int{7},
+			nil,
+			0,
+		},
+		{
+			"Fail, additional season length too short",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			[]int{1},
+			[]float64{0.2},
+			0,
+		},
+		{
+			"Fail, phase beyond the longest season",
+			&holtwinters.ParamError{Op: "prediction", Name: "phase", Value: 7, Range: "at least 0 and less than the season length 7", Err: holtwinters.ErrPhase},
+			[]int{7},
+			[]float64{0.2},
+			7,
+		},
+		{
+			"Fail, additional gamma too high",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 1.500000, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			[]int{7},
+			[]float64{1.5},
+			0,
+		},
+		{
+			"Success, phase within the longest season",
+			nil,
+			[]int{7},
+			[]float64{0.2},
+			6,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			model := holtwinters.NewModel(holtwinters.Additive, 4, 0.2, 0.05, 0.2)
+			model.AdditionalSeasonLengths = test.additionalSeasonLengths
+			model.AdditionalGammas = test.additionalGammas
+			model.Phase = test.phase
+			_, err := model.Fit(multiSeasonalTestSeries(28))
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+)
+
+// NonFinitePolicy is how non-finite observations, NaN and ±Inf, in a series are handled
+type NonFinitePolicy int
+
+const (
+	// SkipNonFinite treats non-finite observations as missing, they are skipped when calculating the initial trend and
+	// seasonal components, and when smoothing the state is not updated for them, this is the default. The series must
+	// hold at least two finite observations
+	SkipNonFinite NonFinitePolicy = iota
+	// RejectNonFinite returns a ParamError wrapping ErrSeriesValue for the first non-finite observation
+	RejectNonFinite
+	// ImputeNonFinite replaces non-finite observations by linear interpolation between the nearest finite observations
+	// either side of them, or with the nearest finite observation at the start and end of the series. When updating a
+	// fitted model the later observations are not known, so a non-finite observation is skipped
+	ImputeNonFinite
+)
+
+// String returns the name of the policy
+func (policy NonFinitePolicy) String() string {
+	switch policy {
+	case SkipNonFinite:
+		return "skip"
+	case RejectNonFinite:
+		return "reject"
+	case ImputeNonFinite:
+		return "impute"
+	}
+	return fmt.Sprintf("NonFinitePolicy(%d)", int(policy))
+}
+
+// missing returns true if the value is a missing observation, which is any non-finite value, NaN or ±Inf
+func missing(val float64) bool {
+	return math.IsNaN(val) || math.IsInf(val, 0)
+}
+
+// applyNonFinitePolicy handles the non-finite observations in the series using the policy provided, returning the
+// series to smooth, which is only a copy of the series if values have been imputed
+func applyNonFinitePolicy(series []float64, policy NonFinitePolicy) ([]float64, error) {
+	switch policy {
+	case SkipNonFinite:
+		err := validateFiniteObservations(series)
+		if err != nil {
+			return nil, err
+		}
+		return series, nil
+	case RejectNonFinite:
+		for i, val := range series {
+			if missing(val) {
+				return nil, &ParamError{Op: "prediction", Name: fmt.Sprintf("series value %d", i), Value: val, Range: "finite", Err: ErrSeriesValue}
+			}
+		}
+		return series, nil
+	case ImputeNonFinite:
+		return imputeNonFinite(series)
+	}
+	return series, nil
+}
+
+// imputeNonFinite returns a copy of the series with each non-finite observation replaced by linear interpolation
+// between the nearest finite observations either side of it, or with the nearest finite observation if there is none
+// on one side
+func imputeNonFinite(series []float64) ([]float64, error) {
+	imputed := make([]float64, len(series))
+	previous := -1
+	for i, val := range series {
+		imputed[i] = val
+		if missing(val) {
+			continue
+		}
+		for j := previous + 1; j < i; j++ {
+			if previous == -1 {
+				imputed[j] = val
+				continue
+			}
+			ratio := float64(j-previous) / float64(i-previous)
+			imputed[j] = series[previous] + ratio*(val-series[previous])
+		}
+		previous = i
+	}
+	if previous == -1 {
+		return nil, &ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 1 to impute from", Err: ErrSeriesValue}
+	}
+	for j := previous + 1; j < len(series); j++ {
+		imputed[j] = series[previous]
+	}
+	return imputed, nil
+}
+
+// validateFiniteObservations ensures the series holds at least two finite observations, the fewest needed to
+// initialise the level and trend when the non-finite observations are skipped
+func validateFiniteObservations(series []float64) error {
+	finite := 0
+	for _, val := range series {
+		if !missing(val) {
+			finite++
+		}
+	}
+	if finite < 2 {
+		return &ParamError{Op: "prediction", Name: "finite values in the series", Value: finite, Range: "at least 2", Err: ErrSeriesValue}
+	}
+	return nil
+}
+
+// validateFinite ensures every value of a result is finite, so that a non-finite result is never returned without an
+// error, which can happen if the series or parameters are unsuitable for the method, such as a multiplicative model of
+// a series with a season averaging 0
+func validateFinite(values []float64) error {
+	for i, val := range values {
+		if missing(val) {
+			return fmt.Errorf("Result is not finite, value %d is %f: %w", i, val, ErrNonFiniteResult)
+		}
+	}
+	return nil
+}
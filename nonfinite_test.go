@@ -0,0 +1,467 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestNonFiniteParameters(t *testing.T) {
+	series := []float64{1, 2, 3, 4, 5, 6}
+
+	var tests = []struct {
+		description string
+		expectedErr error
+		predict     func() error
+	}{
+		{
+			"NaN alpha",
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: math.NaN(), Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			func() error {
+				_, err := holtwinters.PredictAdditive(series, 2, math.NaN(), 0.5, 0.5, 2)
+				return err
+			},
+		},
+		{
+			"NaN beta",
+			&holtwinters.ParamError{Op: "prediction", Name: "beta", Value: math.NaN(), Range: "between 0 and 1", Err: holtwinters.ErrBeta},
+			func() error {
+				_, err := holtwinters.PredictMultiplicative(series, 2, 0.5, math.NaN(), 0.5, 2)
+				return err
+			},
+		},
+		{
+			"Inf gamma",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: math.Inf(1), Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			func() error {
+				_, err := holtwinters.PredictAdditive(series, 2, 0.5, 0.5, math.Inf(1), 2)
+				return err
+			},
+		},
+		{
+			"NaN phi",
+			&holtwinters.ParamError{Op: "prediction", Name: "phi", Value: math.NaN(), Range: "greater than 0 and at most 1", Err: holtwinters.ErrPhi},
+			func() error {
+				_, err := holtwinters.PredictAdditiveDamped(series, 2, 0.5, 0.5, 0.5, math.NaN(), 2)
+				return err
+			},
+		},
+		{
+			"NaN confidence",
+			&holtwinters.ParamError{Op: "prediction", Name: "confidence", Value: math.NaN(), Range: "greater than 0 and less than 1", Err: holtwinters.ErrConfidence},
+			func() error {
+				_, err := holtwinters.PredictAdditiveIntervals(series, 2, 0.5, 0.5, 0.5, 2, math.NaN())
+				return err
+			},
+		},
+		{
+			"NaN model gamma",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: math.NaN(), Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			func() error {
+				_, err := holtwinters.NewModel(holtwinters.Additive, 2, 0.5, 0.5, math.NaN()).Fit(series)
+				return err
+			},
+		},
+		{
+			"Unknown model non-finite policy",
+			&holtwinters.ParamError{Op: "prediction", Name: "non-finite policy", Value: 3, Range: "skip, reject or impute", Err: holtwinters.ErrNonFinitePolicy},
+			func() error {
+				model := holtwinters.NewModel(holtwinters.Additive, 2, 0.5, 0.5, 0.5)
+				model.NonFinite = holtwinters.NonFinitePolicy(3)
+				_, err := model.Fit(series)
+				return err
+			},
+		},
+		{
+			"NaN multiple seasonality gamma",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: math.NaN(), Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			func() error {
+				_, err := holtwinters.PredictAdditiveMultiSeasonal(series, []int{2, 3}, 0.5, 0.5, []float64{0.5, math.NaN()}, 2)
+				return err
+			},
+		},
+		{
+			"NaN aberration delta",
+			&holtwinters.ParamError{Op: "aberration detection", Name: "delta", Value: math.NaN(), Range: "greater than 0 and finite"},
+			func() error {
+				_, err := holtwinters.NewModel(holtwinters.Additive, 2, 0.5, 0.5, 0.5).DetectAberrations(series, holtwinters.AberrationConfig{GammaDeviation: 0.5, Delta: math.NaN(), Window: 1, Threshold: 1})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.predict()
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+			}
+			if !errors.Is(err, holtwinters.ErrInvalidParameter) {
+				t.Errorf("expected error to match ErrInvalidParameter, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTooFewFiniteObservations(t *testing.T) {
+	nan := math.NaN()
+	missingSeries := []float64{nan, nan, nan, nan, nan, nan, nan, nan}
+	singleSeries := []float64{nan, nan, nan, 5, nan, nan, nan, nan}
+
+	var tests = []struct {
+		description string
+		expectedErr error
+		predict     func() error
+	}{
+		{
+			"Every value missing",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.PredictAdditive(missingSeries, 4, 0.5, 0.5, 0.5, 3)
+				return err
+			},
+		},
+		{
+			"Single value present",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.PredictAdditive(singleSeries, 4, 0.5, 0.5, 0.5, 3)
+				return err
+			},
+		},
+		{
+			"Every value infinite",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.NewModel(holtwinters.Multiplicative, 2, 0.5, 0.5, 0.5).Fit([]float64{math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)})
+				return err
+			},
+		},
+		{
+			"Parameter estimation, every value missing",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.EstimateParameters(missingSeries, 4, holtwinters.Additive)
+				return err
+			},
+		},
+		{
+			"Multiple seasonality, single value present",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.PredictAdditiveMultiSeasonal(singleSeries, []int{2, 4}, 0.5, 0.5, []float64{0.5, 0.5}, 3)
+				return err
+			},
+		},
+		{
+			"Box-Cox, every value missing",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.PredictAdditiveBoxCox(missingSeries, 4, 0.5, 0.5, 0.5, 3, holtwinters.BoxCox{Lambda: 0.5})
+				return err
+			},
+		},
+		{
+			"ETS, single value present",
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeriesValue},
+			func() error {
+				_, err := holtwinters.FitETS(singleSeries, 4, holtwinters.ETSSpec{Error: holtwinters.AdditiveError, Trend: holtwinters.AdditiveTrend, Season: holtwinters.AdditiveSeason})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := test.predict()
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+			}
+			if !errors.Is(err, holtwinters.ErrSeriesValue) {
+				t.Errorf("expected error to match ErrSeriesValue, got %v", err)
+			}
+		})
+	}
+}
+
+func TestModelFitNonFinitePolicy(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-12)
+
+	fit := func(series []float64) []float64 {
+		result, err := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3).Fit(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		policy      holtwinters.NonFinitePolicy
+		series      []float64
+	}{
+		{
+			"Fail, reject NaN",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series value 5", Value: math.NaN(), Range: "finite", Err: holtwinters.ErrSeriesValue},
+			holtwinters.RejectNonFinite,
+			[]float64{10, 20, 30, 20, 11, math.NaN(), 29, 20, 10, 19},
+		},
+		{
+			"Fail, reject Inf",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "series value 2", Value: math.Inf(-1), Range: "finite", Err: holtwinters.ErrSeriesValue},
+			holtwinters.RejectNonFinite,
+			[]float64{10, 20, math.Inf(-1), 20, 11, 21, 29, 20, 10, 19},
+		},
+		{
+			"Fail, impute with no finite values",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "finite values in the series", Value: 0, Range: "at least 1 to impute from", Err: holtwinters.ErrSeriesValue},
+			holtwinters.ImputeNonFinite,
+			[]float64{math.NaN(), math.NaN(), math.Inf(1), math.NaN()},
+		},
+		{
+			"Success, reject with every value finite",
+			fit([]float64{10, 20, 30, 20, 11, 21, 29, 20, 10, 19}),
+			nil,
+			holtwinters.RejectNonFinite,
+			[]float64{10, 20, 30, 20, 11, 21, 29, 20, 10, 19},
+		},
+		{
+			"Success, skip treats Inf as missing",
+			fit([]float64{10, 20, 30, 20, 11, math.NaN(), 29, math.NaN(), 10, 19}),
+			nil,
+			holtwinters.SkipNonFinite,
+			[]float64{10, 20, 30, 20, 11, math.Inf(1), 29, math.Inf(-1), 10, 19},
+		},
+		{
+			"Success, impute interpolates",
+			fit([]float64{10, 20, 30, 20, 11, 17, 23, 29, 10, 19}),
+			nil,
+			holtwinters.ImputeNonFinite,
+			[]float64{10, 20, 30, 20, 11, math.NaN(), math.Inf(1), 29, 10, 19},
+		},
+		{
+			"Success, impute fills the start and end with the nearest value",
+			fit([]float64{20, 20, 30, 20, 11, 21, 29, 20, 19, 19}),
+			nil,
+			holtwinters.ImputeNonFinite,
+			[]float64{math.NaN(), 20, 30, 20, 11, 21, 29, 20, 19, math.NaN()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			model := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3)
+			model.NonFinite = test.policy
+			result, err := model.Fit(test.series)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result, equateApprox) {
+				t.Errorf("smoothed mismatch (-want +got):\n%s", cmp.Diff(test.expected, result, equateApprox))
+			}
+		})
+	}
+}
+
+func TestModelUpdateNonFinitePolicy(t *testing.T) {
+	series := []float64{10, 20, 30, 20, 11, 21, 29, 20}
+
+	model := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3)
+	model.NonFinite = holtwinters.RejectNonFinite
+	_, err := model.Fit(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = model.Update(math.Inf(1))
+	if !errors.Is(err, holtwinters.ErrSeriesValue) {
+		t.Errorf("expected error to match ErrSeriesValue, got %v", err)
+	}
+
+	// Skipping and imputing both treat the observation as missing when updating
+	for _, policy := range []holtwinters.NonFinitePolicy{holtwinters.SkipNonFinite, holtwinters.ImputeNonFinite} {
+		expected := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3)
+		model := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3)
+		model.NonFinite = policy
+		_, err := expected.Fit(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = model.Fit(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want, err := expected.Update(math.NaN())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := model.Update(math.Inf(-1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want != got {
+			t.Errorf("%s smoothed value mismatch, want %v, got %v", policy, want, got)
+		}
+	}
+}
+
+func TestModelUpdateNonFiniteResult(t *testing.T) {
+	series := []float64{10, 20, 30, 20, 11, 21, 29, 20}
+
+	model := holtwinters.NewModel(holtwinters.Additive, 4, 0.5, 0.2, 0.3)
+	_, err := model.Fit(series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := *model
+	expected.Seasonals = [][]float64{append([]float64{}, model.Seasonals[0]...)}
+
+	// The smoothed value is finite, but the trend it leaves overflows when forecasting the next season
+	_, err = model.Update(math.MaxFloat64)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+	}
+	if !cmp.Equal(&expected, model, cmpopts.IgnoreUnexported(holtwinters.Model{})) {
+		t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(&expected, model, cmpopts.IgnoreUnexported(holtwinters.Model{})))
+	}
+	_, err = model.Forecast(4)
+	if err != nil {
+		t.Errorf("unexpected error forecasting after rejected update: %v", err)
+	}
+}
+
+func TestNonFiniteResult(t *testing.T) {
+	// A season averaging 0 gives NaN seasonal components for the multiplicative method
+	series := []float64{0, 0, 0, 0, 0, 0}
+
+	_, err := holtwinters.PredictMultiplicative(series, 2, 0.5, 0.5, 0.5, 2)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+	}
+	_, err = holtwinters.NewModel(holtwinters.Multiplicative, 2, 0.5, 0.5, 0.5).Fit(series)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+	}
+	_, err = holtwinters.PredictMultiplicativeLegacy(series, 2, 0.5, 0.5, 0.5, 2)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected legacy error to match ErrNonFiniteResult, got %v", err)
+	}
+	_, err = holtwinters.EstimateParameters(series, 2, holtwinters.Multiplicative)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected estimate error to match ErrNonFiniteResult, got %v", err)
+	}
+	_, err = holtwinters.PredictMultiplicativeMultiSeasonal(series, []int{2, 3}, 0.5, 0.5, []float64{0.5, 0.5}, 2)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+	}
+	if errors.Is(err, holtwinters.ErrInvalidParameter) {
+		t.Errorf("expected error not to match ErrInvalidParameter, got %v", err)
+	}
+
+	// A first season averaging 0 gives a NaN level once the multiplicative seasonal component divides by it
+	series = []float64{0, 0, 0, 0, 1, 2, 3, 4}
+	_, err = holtwinters.NewModel(holtwinters.Multiplicative, 4, 0.5, 0.5, 0.5).Decompose(series)
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected decomposition error to match ErrNonFiniteResult, got %v", err)
+	}
+	_, err = holtwinters.NewModel(holtwinters.Multiplicative, 4, 0.5, 0.5, 0.5).DetectAberrations(series, holtwinters.AberrationConfig{
+		GammaDeviation: 0.1,
+		Delta:          2,
+		Window:         1,
+		Threshold:      1,
+	})
+	if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+		t.Errorf("expected aberration detection error to match ErrNonFiniteResult, got %v", err)
+	}
+}
+
+func TestModelFitNonFiniteResultUnchanged(t *testing.T) {
+	// A first season averaging 0 gives a NaN level once the multiplicative seasonal component divides by it
+	invalid := []float64{0, 0, 0, 0, 1, 2, 3, 4}
+	config := holtwinters.AberrationConfig{
+		GammaDeviation: 0.1,
+		Delta:          2,
+		Window:         1,
+		Threshold:      1,
+	}
+
+	var tests = []struct {
+		description string
+		fit         func(model *holtwinters.Model) error
+	}{
+		{
+			"Fit",
+			func(model *holtwinters.Model) error {
+				_, err := model.Fit(invalid)
+				return err
+			},
+		},
+		{
+			"Decompose",
+			func(model *holtwinters.Model) error {
+				_, err := model.Decompose(invalid)
+				return err
+			},
+		},
+		{
+			"DetectAberrations",
+			func(model *holtwinters.Model) error {
+				_, err := model.DetectAberrations(invalid, config)
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			unfitted := holtwinters.NewModel(holtwinters.Multiplicative, 4, 0.5, 0.5, 0.5)
+			err := test.fit(unfitted)
+			if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+				t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+			}
+			if unfitted.Observations != 0 {
+				t.Errorf("unfitted model fitted by failure, observations %d", unfitted.Observations)
+			}
+
+			fitted := holtwinters.NewModel(holtwinters.Multiplicative, 4, 0.5, 0.5, 0.5)
+			_, err = fitted.Fit([]float64{10, 20, 30, 20, 11, 21, 29, 20})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := *fitted
+			expected.Seasonals = [][]float64{append([]float64{}, fitted.Seasonals[0]...)}
+			err = test.fit(fitted)
+			if !errors.Is(err, holtwinters.ErrNonFiniteResult) {
+				t.Errorf("expected error to match ErrNonFiniteResult, got %v", err)
+			}
+			if !cmp.Equal(&expected, fitted, cmp.AllowUnexported(holtwinters.Model{})) {
+				t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(&expected, fitted, cmp.AllowUnexported(holtwinters.Model{})))
+			}
+			_, err = fitted.Forecast(4)
+			if err != nil {
+				t.Errorf("unexpected error forecasting after failed fit: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"time"
+)
+
+// Point is an observation of a series at a point in time
+type Point struct {
+	// Time is when the observation was made
+	Time time.Time
+	// Value is the observation, NaN if the observation is missing
+	Value float64
+}
+
+// PredictPoints takes in a seasonal historical series of timestamped observations, evenly spaced by the step
+// provided, and produces a prediction of what the data will be in the future in the same way as Predict. Returns the
+// smoothed observations at their original times, with the predictions appended to the end, timestamped one step apart
+// following the last observation.
+// points - Historical seasonal data, must be at least a full season, for optimal results use at least two full seasons,
+// in time order and evenly spaced by the step, the first point should be at the position in the season given by the
+// config's Phase
+// step - The time between each observation, must be greater than 0
+// config - The method, season length, smoothing coefficients and number of predictions to make
+func PredictPoints(points []Point, step time.Duration, config Config) ([]Point, error) {
+	err := validatePoints(points, step)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]float64, len(points))
+	for i, point := range points {
+		series[i] = point.Value
+	}
+	result, err := Predict(series, config)
+	if err != nil {
+		return nil, err
+	}
+	return timestampPoints(points, step, result), nil
+}
+
+// timestampPoints pairs each value with a time, values within the points provided are given the time of the point,
+// and values following them are given times one step apart following the last point
+func timestampPoints(points []Point, step time.Duration, values []float64) []Point {
+	result := make([]Point, len(values))
+	last := points[len(points)-1].Time
+	for i, val := range values {
+		if i < len(points) {
+			result[i] = Point{Time: points[i].Time, Value: val}
+			continue
+		}
+		result[i] = Point{Time: last.Add(time.Duration(i-len(points)+1) * step), Value: val}
+	}
+	return result
+}
+
+// validatePoints ensures the step is valid and the points are in time order, evenly spaced by it
+func validatePoints(points []Point, step time.Duration) error {
+	if step <= 0 {
+		return &ParamError{Op: "prediction", Name: "step", Value: step, Range: "greater than 0", Err: ErrStep}
+	}
+	for i := 1; i < len(points); i++ {
+		interval := points[i].Time.Sub(points[i-1].Time)
+		if interval != step {
+			return &ParamError{Op: "prediction", Name: fmt.Sprintf("time between points %d and %d", i-1, i), Value: interval, Range: fmt.Sprintf("the step %s", step), Err: ErrStep}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestPredictPoints(t *testing.T) {
+	start := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	series := []float64{10, 20, 30, 20, 11, 21, 29, 20, 10, 19}
+	points := make([]holtwinters.Point, len(series))
+	for i, val := range series {
+		points[i] = holtwinters.Point{Time: start.Add(time.Duration(i) * time.Hour), Value: val}
+	}
+	config := holtwinters.Config{SeasonLength: 4, Alpha: 0.5, Beta: 0.2, Gamma: 0.3, PredictionLength: 3}
+	prediction, err := holtwinters.Predict(series, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := make([]holtwinters.Point, len(prediction))
+	for i, val := range prediction {
+		expected[i] = holtwinters.Point{Time: start.Add(time.Duration(i) * time.Hour), Value: val}
+	}
+
+	var tests = []struct {
+		description string
+		expected    []holtwinters.Point
+		expectedErr error
+		points      []holtwinters.Point
+		step        time.Duration
+		config      holtwinters.Config
+	}{
+		{
+			"Fail, step not positive",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "step", Value: time.Duration(0), Range: "greater than 0", Err: holtwinters.ErrStep},
+			points,
+			0,
+			config,
+		},
+		{
+			"Fail, gap between points",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "time between points 2 and 3", Value: 2 * time.Hour, Range: "the step 1h0m0s", Err: holtwinters.ErrStep},
+			append(append([]holtwinters.Point{}, points[:3]...), holtwinters.Point{Time: start.Add(4 * time.Hour), Value: 20}),
+			time.Hour,
+			config,
+		},
+		{
+			"Fail, points out of order",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "time between points 0 and 1", Value: -time.Hour, Range: "the step 1h0m0s", Err: holtwinters.ErrStep},
+			[]holtwinters.Point{points[1], points[0]},
+			time.Hour,
+			config,
+		},
+		{
+			"Fail, invalid config",
+			nil,
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 1.5, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			points,
+			time.Hour,
+			holtwinters.Config{SeasonLength: 4, Alpha: 1.5, Beta: 0.2, Gamma: 0.3, PredictionLength: 3},
+		},
+		{
+			"Success, forecasts follow the last point",
+			expected,
+			nil,
+			points,
+			time.Hour,
+			config,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.PredictPoints(test.points, test.step, test.config)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("points mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
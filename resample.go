@@ -0,0 +1,131 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MaxResampleLength is the most points Resample will produce, bounding the memory used when the points span a long
+// time relative to the step, such as when a single point has a bad timestamp
+const MaxResampleLength = 1000000
+
+// Aggregation is how the observations falling within a step are combined when resampling
+type Aggregation int
+
+const (
+	// MeanAggregation takes the mean of the observations
+	MeanAggregation Aggregation = iota
+	// SumAggregation takes the sum of the observations
+	SumAggregation
+	// LastAggregation takes the latest observation
+	LastAggregation
+	// MaxAggregation takes the largest observation
+	MaxAggregation
+)
+
+// String returns the name of the aggregation
+func (aggregation Aggregation) String() string {
+	switch aggregation {
+	case MeanAggregation:
+		return "mean"
+	case SumAggregation:
+		return "sum"
+	case LastAggregation:
+		return "last"
+	case MaxAggregation:
+		return "max"
+	}
+	return fmt.Sprintf("Aggregation(%d)", int(aggregation))
+}
+
+// Resample buckets irregularly spaced timestamped observations onto a regular grid of the step provided, combining
+// the observations within each step using the aggregation provided. The grid starts at the time of the earliest
+// observation truncated to a multiple of the step, and ends with the step holding the latest observation, each point
+// is timestamped with the start of its step. Steps with no observations are gaps, and are given a missing (NaN) value.
+// Missing (non-finite) observations are skipped. The points returned are evenly spaced by the step, so can be provided
+// to PredictPoints, or their values to any of the prediction functions.
+// points - Timestamped observations, in any order, must hold at least one observation, and span at most
+// MaxResampleLength steps
+// step - The time between each point of the grid, must be greater than 0
+// aggregation - How the observations within each step are combined
+func Resample(points []Point, step time.Duration, aggregation Aggregation) ([]Point, error) {
+	if step <= 0 {
+		return nil, &ParamError{Op: "resampling", Name: "step", Value: step, Range: "greater than 0", Err: ErrStep}
+	}
+	if aggregation < MeanAggregation || aggregation > MaxAggregation {
+		return nil, &ParamError{Op: "resampling", Name: "aggregation", Value: int(aggregation), Range: "mean, sum, last or max"}
+	}
+	if len(points) == 0 {
+		return nil, &ParamError{Op: "resampling", Name: "series length", Value: 0, Range: "at least 1", Err: ErrSeriesLength}
+	}
+
+	// Sorting is stable so that the last of observations at the same time is the one that came last
+	sorted := append([]Point{}, points...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	start := sorted[0].Time.Truncate(step)
+	last := sorted[len(sorted)-1].Time
+	// The time between two points saturates at the largest Duration, which would misplace the later points
+	span := last.Sub(start)
+	if !start.Add(span).Equal(last) {
+		return nil, &ParamError{Op: "resampling", Name: "time between the first and last points", Value: fmt.Sprintf("%s to %s", sorted[0].Time, last), Range: fmt.Sprintf("at most %s", time.Duration(math.MaxInt64)), Err: ErrSeriesLength}
+	}
+	if span/step >= MaxResampleLength {
+		return nil, &ParamError{Op: "resampling", Name: "resampled series length", Value: int64(span/step) + 1, Range: fmt.Sprintf("at most %d", MaxResampleLength), Err: ErrSeriesLength}
+	}
+	length := int(span/step) + 1
+
+	values := make([]float64, length)
+	counts := make([]int, length)
+	for _, point := range sorted {
+		if missing(point.Value) {
+			continue
+		}
+		i := int(point.Time.Sub(start) / step)
+		if counts[i] == 0 {
+			values[i] = point.Value
+			counts[i]++
+			continue
+		}
+		switch aggregation {
+		case MeanAggregation, SumAggregation:
+			values[i] += point.Value
+		case LastAggregation:
+			values[i] = point.Value
+		case MaxAggregation:
+			values[i] = math.Max(values[i], point.Value)
+		}
+		counts[i]++
+	}
+
+	result := make([]Point, length)
+	for i := range result {
+		value := values[i]
+		if counts[i] == 0 {
+			value = math.NaN()
+		} else if aggregation == MeanAggregation {
+			value /= float64(counts[i])
+		}
+		result[i] = Point{Time: start.Add(time.Duration(i) * step), Value: value}
+	}
+	return result, nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestResample(t *testing.T) {
+	start := time.Date(2019, 12, 20, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	// Observations at irregular times, out of order, with an empty step from 10:20 to 10:30
+	points := []holtwinters.Point{
+		{Time: at(3), Value: 4},
+		{Time: at(7), Value: 2},
+		{Time: at(12), Value: 5},
+		{Time: at(1), Value: 3},
+		{Time: at(35), Value: 1},
+		{Time: at(16), Value: math.NaN()},
+		{Time: at(31), Value: 6},
+	}
+
+	var tests = []struct {
+		description string
+		expected    []float64
+		expectedErr error
+		points      []holtwinters.Point
+		step        time.Duration
+		aggregation holtwinters.Aggregation
+	}{
+		{
+			"Fail, step not positive",
+			nil,
+			&holtwinters.ParamError{Op: "resampling", Name: "step", Value: -time.Minute, Range: "greater than 0", Err: holtwinters.ErrStep},
+			points,
+			-time.Minute,
+			holtwinters.MeanAggregation,
+		},
+		{
+			"Fail, unknown aggregation",
+			nil,
+			&holtwinters.ParamError{Op: "resampling", Name: "aggregation", Value: 4, Range: "mean, sum, last or max"},
+			points,
+			10 * time.Minute,
+			holtwinters.Aggregation(4),
+		},
+		{
+			"Fail, span overflows a duration",
+			nil,
+			&holtwinters.ParamError{Op: "resampling", Name: "time between the first and last points", Value: "0001-01-01 00:00:00 +0000 UTC to 2026-01-01 00:00:00 +0000 UTC", Range: "at most 2562047h47m16.854775807s", Err: holtwinters.ErrSeriesLength},
+			[]holtwinters.Point{
+				{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Value: 1},
+				{Time: time.Time{}, Value: 2},
+			},
+			time.Hour,
+			holtwinters.MeanAggregation,
+		},
+		{
+			"Fail, too many steps",
+			nil,
+			&holtwinters.ParamError{Op: "resampling", Name: "resampled series length", Value: int64(2678401), Range: "at most 1000000", Err: holtwinters.ErrSeriesLength},
+			[]holtwinters.Point{
+				{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Value: 1},
+				{Time: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Value: 2},
+			},
+			time.Second,
+			holtwinters.MeanAggregation,
+		},
+		{
+			"Fail, no observations",
+			nil,
+			&holtwinters.ParamError{Op: "resampling", Name: "series length", Value: 0, Range: "at least 1", Err: holtwinters.ErrSeriesLength},
+			[]holtwinters.Point{},
+			10 * time.Minute,
+			holtwinters.MeanAggregation,
+		},
+		{
+			"Success, mean",
+			[]float64{3, 5, math.NaN(), 3.5},
+			nil,
+			points,
+			10 * time.Minute,
+			holtwinters.MeanAggregation,
+		},
+		{
+			"Success, sum",
+			[]float64{9, 5, math.NaN(), 7},
+			nil,
+			points,
+			10 * time.Minute,
+			holtwinters.SumAggregation,
+		},
+		{
+			"Success, last",
+			[]float64{2, 5, math.NaN(), 1},
+			nil,
+			points,
+			10 * time.Minute,
+			holtwinters.LastAggregation,
+		},
+		{
+			"Success, max",
+			[]float64{4, 5, math.NaN(), 6},
+			nil,
+			points,
+			10 * time.Minute,
+			holtwinters.MaxAggregation,
+		},
+		{
+			"Success, last of observations at the same time is the latest provided",
+			[]float64{8},
+			nil,
+			[]holtwinters.Point{{Time: at(5), Value: 7}, {Time: at(5), Value: 8}},
+			10 * time.Minute,
+			holtwinters.LastAggregation,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := holtwinters.Resample(test.points, test.step, test.aggregation)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+			if err != nil {
+				return
+			}
+			expected := make([]holtwinters.Point, len(test.expected))
+			for i, val := range test.expected {
+				expected[i] = holtwinters.Point{Time: start.Add(time.Duration(i) * test.step), Value: val}
+			}
+			if !cmp.Equal(expected, result, cmpopts.EquateNaNs()) {
+				t.Errorf("resampled mismatch (-want +got):\n%s", cmp.Diff(expected, result, cmpopts.EquateNaNs()))
+			}
+		})
+	}
+}
+
+func TestResamplePredictPoints(t *testing.T) {
+	// Two observations in most hours, at irregular minutes, with the hour starting at 05:00 having none
+	start := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	points := []holtwinters.Point{}
+	for hour := 0; hour < 16; hour++ {
+		if hour == 5 {
+			continue
+		}
+		val := []float64{10, 20, 30, 20}[hour%4] + float64(hour)
+		points = append(points,
+			holtwinters.Point{Time: start.Add(time.Duration(hour)*time.Hour + time.Duration(hour*3)*time.Minute), Value: val - 1},
+			holtwinters.Point{Time: start.Add(time.Duration(hour)*time.Hour + 50*time.Minute), Value: val + 1},
+		)
+	}
+
+	resampled, err := holtwinters.Resample(points, time.Hour, holtwinters.MeanAggregation)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := holtwinters.PredictPoints(resampled, time.Hour, holtwinters.Config{SeasonLength: 4, Alpha: 0.5, Beta: 0.2, Gamma: 0.3, PredictionLength: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	series := []float64{10, 21, 32, 23, 14, math.NaN(), 36, 27, 18, 29, 40, 31, 22, 33, 44, 35}
+	prediction, err := holtwinters.PredictAdditive(series, 4, 0.5, 0.2, 0.3, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := make([]holtwinters.Point, len(prediction))
+	for i, val := range prediction {
+		expected[i] = holtwinters.Point{Time: start.Add(time.Duration(i) * time.Hour), Value: val}
+	}
+	if !cmp.Equal(expected, result) {
+		t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+	}
+}
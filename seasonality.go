@@ -0,0 +1,239 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// AutoSeasonLength can be provided as the season length to detect the season length of the series automatically using
+// DetectSeasonLength, choosing the strongest candidate if it is significant
+const AutoSeasonLength = -1
+
+// autoSeasonSignificance is the significance level a candidate's strength must reach for it to be significant, so that
+// autocorrelation from noise is not mistaken for seasonality
+const autoSeasonSignificance = 0.05
+
+// SeasonCandidate is a possible season length for a series
+type SeasonCandidate struct {
+	// SeasonLength is the candidate season length
+	SeasonLength int
+	// Strength is the autocorrelation of the detrended series at a lag of the season length, between -1 and 1, higher
+	// values mean a stronger seasonal pattern
+	Strength float64
+	// Significant is true if the strength is significant at the 5% level, compared with the autocorrelation of white
+	// noise and corrected for the number of season lengths considered
+	Significant bool
+}
+
+// DetectSeasonLength estimates the season length of a series using its autocorrelation. The series is detrended using
+// a least squares linear fit, and candidates are the lags at which the autocorrelation of the detrended series peaks
+// above 0. Returns the candidates in order of strength, strongest first, which is empty if no seasonality is found.
+// Missing (NaN) values are skipped. AutoSeasonLength only uses the strongest candidate if it is significant.
+// series - Historical data, for a season length to be detected there must be at least two full seasons
+// maxSeasonLength - The longest season length to consider, must be at least 2, set to 0 to consider every season
+// length for which there are at least two full seasons of data
+func DetectSeasonLength(series []float64, maxSeasonLength int) ([]SeasonCandidate, error) {
+	if maxSeasonLength != 0 && maxSeasonLength < 2 {
+		return nil, &ParamError{Op: "season length detection", Name: "max season length", Value: maxSeasonLength, Range: "at least 2 or 0"}
+	}
+	maxLag := len(series) / 2
+	if maxSeasonLength != 0 && maxSeasonLength < maxLag {
+		maxLag = maxSeasonLength
+	}
+
+	candidates := []SeasonCandidate{}
+	if maxLag < 2 {
+		return candidates, nil
+	}
+
+	// Autocorrelation up to one lag past the max so peaks at the max can be detected
+	acf := autocorrelation(detrend(series), maxLag+1)
+	threshold := significantStrength(series, maxLag-1)
+	for lag := 2; lag <= maxLag; lag++ {
+		if acf[lag] <= 0 || acf[lag] <= acf[lag-1] || (lag+1 < len(acf) && acf[lag] < acf[lag+1]) {
+			continue
+		}
+		candidates = append(candidates, SeasonCandidate{
+			SeasonLength: lag,
+			Strength:     acf[lag],
+			Significant:  acf[lag] > threshold,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Strength > candidates[j].Strength
+	})
+	return candidates, nil
+}
+
+// resolveSeasonLength returns the season length provided, unless it is AutoSeasonLength in which case the strongest
+// season length detected for the series is returned, as long as it is significant
+func resolveSeasonLength(series []float64, seasonLength int) (int, error) {
+	if seasonLength != AutoSeasonLength {
+		return seasonLength, nil
+	}
+	candidates, err := DetectSeasonLength(series, 0)
+	if err != nil {
+		return 0, err
+	}
+	if len(candidates) == 0 || !candidates[0].Significant {
+		return 0, &ParamError{Op: "prediction", Name: "season length", Value: "AutoSeasonLength", Range: "provided, as no season length could be detected from the series", Err: ErrSeasonLength}
+	}
+	return candidates[0].SeasonLength, nil
+}
+
+// significantStrength returns the autocorrelation a candidate detected from the series must exceed to be significant at
+// autoSeasonSignificance. If the series were white noise, the autocorrelation at each lag would be roughly normally
+// distributed with a standard error of 1/√n, the significance level is divided by the number of lags searched, as the
+// strongest of them is chosen
+func significantStrength(series []float64, lags int) float64 {
+	n := 0
+	for _, val := range series {
+		if !missing(val) {
+			n++
+		}
+	}
+	if n == 0 || lags < 1 {
+		return math.Inf(1)
+	}
+	z := math.Sqrt2 * math.Erfinv(1-2*autoSeasonSignificance/float64(lags))
+	return z / math.Sqrt(float64(n))
+}
+
+// detrend removes a least squares linear fit from the series, missing (NaN) values are left as NaN
+func detrend(series []float64) []float64 {
+	intercept, slope := linearFit(series)
+	detrended := make([]float64, len(series))
+	for i, val := range series {
+		detrended[i] = val - (intercept + slope*float64(i))
+	}
+	return detrended
+}
+
+// linearFit calculates the intercept and slope of a least squares linear fit of the series against its indices,
+// skipping missing (NaN) values
+func linearFit(series []float64) (intercept float64, slope float64) {
+	var n, sumX, sumY, sumXX, sumXY float64
+	for i, val := range series {
+		if missing(val) {
+			continue
+		}
+		x := float64(i)
+		n++
+		sumX += x
+		sumY += val
+		sumXX += x * x
+		sumXY += x * val
+	}
+
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	if n > 0 {
+		intercept = (sumY - slope*sumX) / n
+	}
+	return intercept, slope
+}
+
+// autocorrelation calculates the autocorrelation of the series for lags 0 up to the max lag provided, skipping pairs
+// with missing (NaN) values. Uses the standard estimator, normalising by the variance of the whole series, so that
+// autocorrelation at longer lags with fewer pairs is reduced. The sums for every lag are calculated at once using the
+// fast Fourier transform, taking O(n log n) time rather than O(n²)
+func autocorrelation(series []float64, maxLag int) []float64 {
+	acf := make([]float64, maxLag+1)
+
+	n := float64(0)
+	mean := float64(0)
+	for _, val := range series {
+		if !missing(val) {
+			mean += val
+			n++
+		}
+	}
+	if n == 0 {
+		return acf
+	}
+	mean /= n
+
+	variance := float64(0)
+	for _, val := range series {
+		if !missing(val) {
+			variance += (val - mean) * (val - mean)
+		}
+	}
+	if variance == 0 {
+		return acf
+	}
+
+	// Zero padded to at least twice the length of the series so that the transform's circular correlation does not
+	// wrap around, missing values are left as 0 so that pairs including them add nothing to the sums
+	size := 1
+	for size < 2*len(series) {
+		size *= 2
+	}
+	values := make([]complex128, size)
+	for i, val := range series {
+		if !missing(val) {
+			values[i] = complex(val-mean, 0)
+		}
+	}
+	fft(values, false)
+	for i, val := range values {
+		values[i] = complex(real(val)*real(val)+imag(val)*imag(val), 0)
+	}
+	fft(values, true)
+
+	for lag := 0; lag <= maxLag && lag < len(series); lag++ {
+		acf[lag] = real(values[lag]) / float64(size) / variance
+	}
+	return acf
+}
+
+// fft calculates the discrete Fourier transform of the values in place using the radix-2 Cooley-Tukey algorithm, or the
+// inverse transform without dividing by the number of values, which must be a power of 2
+func fft(values []complex128, inverse bool) {
+	n := len(values)
+	// Reorder the values by the bit reversal of their indices
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	sign := float64(-1)
+	if inverse {
+		sign = 1
+	}
+	for length := 2; length <= n; length *= 2 {
+		half := length / 2
+		angle := sign * 2 * math.Pi / float64(length)
+		for k := 0; k < half; k++ {
+			twiddle := cmplx.Rect(1, angle*float64(k))
+			for start := 0; start < n; start += length {
+				even, odd := values[start+k], values[start+k+half]*twiddle
+				values[start+k], values[start+k+half] = even+odd, even-odd
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+// weeklySeries returns a series with a linear trend and a season length of 7, with the values at the indices provided
+// missing
+func weeklySeries(missing ...int) []float64 {
+	series := []float64{}
+	for i := 0; i < 70; i++ {
+		series = append(series, 10+0.5*float64(i)+3*math.Sin(2*math.Pi*float64(i)/7))
+	}
+	for _, i := range missing {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+func TestDetectSeasonLength(t *testing.T) {
+	equateApprox := cmpopts.EquateApprox(0, 1e-9)
+
+	var tests = []struct {
+		description     string
+		expected        []holtwinters.SeasonCandidate
+		expectedErr     error
+		series          []float64
+		maxSeasonLength int
+	}{
+		{
+			"Fail, max season length too short",
+			nil,
+			&holtwinters.ParamError{Op: "season length detection", Name: "max season length", Value: 1, Range: "at least 2 or 0"},
+			modelTestSeries,
+			1,
+		},
+		{
+			"Success, no seasonality",
+			[]holtwinters.SeasonCandidate{},
+			nil,
+			[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			0,
+		},
+		{
+			"Success, not enough data for two seasons",
+			[]holtwinters.SeasonCandidate{},
+			nil,
+			[]float64{1, 2, 1},
+			0,
+		},
+		{
+			"Success, season length 12 and its multiples",
+			[]holtwinters.SeasonCandidate{
+				{SeasonLength: 12, Strength: 0.6484384668354973, Significant: true},
+				{SeasonLength: 24, Strength: 0.4610695019722801, Significant: true},
+				{SeasonLength: 36, Strength: 0.33398424315207365, Significant: false},
+			},
+			nil,
+			modelTestSeries,
+			0,
+		},
+		{
+			"Success, max season length",
+			[]holtwinters.SeasonCandidate{
+				{SeasonLength: 12, Strength: 0.6484384668354973, Significant: true},
+			},
+			nil,
+			modelTestSeries,
+			20,
+		},
+		{
+			"Success, season length 7 with trend",
+			[]holtwinters.SeasonCandidate{
+				{SeasonLength: 7, Strength: 0.8989485949667649, Significant: true},
+				{SeasonLength: 14, Strength: 0.7979609114506953, Significant: true},
+				{SeasonLength: 21, Strength: 0.6971006709689576, Significant: true},
+				{SeasonLength: 28, Strength: 0.5964315950387169, Significant: true},
+				{SeasonLength: 35, Strength: 0.49601740517713927, Significant: true},
+			},
+			nil,
+			weeklySeries(),
+			0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			candidates, err := holtwinters.DetectSeasonLength(test.series, test.maxSeasonLength)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, candidates, equateApprox) {
+				t.Errorf("candidates mismatch (-want +got):\n%s", cmp.Diff(test.expected, candidates, equateApprox))
+			}
+		})
+	}
+}
+
+func TestDetectSeasonLengthMissingValues(t *testing.T) {
+	candidates, err := holtwinters.DetectSeasonLength(weeklySeries(3, 10, 11, 40), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(candidates) == 0 || candidates[0].SeasonLength != 7 {
+		t.Errorf("expected strongest candidate season length 7, got %+v", candidates)
+	}
+}
+
+func TestAutoSeasonLength(t *testing.T) {
+	expected, err := holtwinters.PredictAdditive(modelTestSeries, 12, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prediction, err := holtwinters.PredictAdditive(modelTestSeries, holtwinters.AutoSeasonLength, 0.716, 0.029, 0.993, 24)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(expected, prediction) {
+		t.Errorf("prediction mismatch (-want +got):\n%s", cmp.Diff(expected, prediction))
+	}
+
+	model := holtwinters.NewModel(holtwinters.Multiplicative, holtwinters.AutoSeasonLength, 0.716, 0.029, 0.993)
+	_, err = model.Fit(modelTestSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.SeasonLength != 12 {
+		t.Errorf("model season length mismatch, want 12, got %d", model.SeasonLength)
+	}
+
+	_, err = holtwinters.PredictAdditive([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, holtwinters.AutoSeasonLength, 0.716, 0.029, 0.993, 24)
+	expectedErr := &holtwinters.ParamError{Op: "prediction", Name: "season length", Value: "AutoSeasonLength", Range: "provided, as no season length could be detected from the series", Err: holtwinters.ErrSeasonLength}
+	if !cmp.Equal(error(expectedErr), err, equateErrors) {
+		t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(error(expectedErr), err, equateErrors))
+	}
+
+	// White noise has autocorrelation peaks, the strongest at 27 with a strength of 0.19, which are not significant
+	random := rand.New(rand.NewSource(1))
+	noise := make([]float64, 200)
+	for i := range noise {
+		noise[i] = random.NormFloat64()
+	}
+	_, err = holtwinters.PredictAdditive(noise, holtwinters.AutoSeasonLength, 0.716, 0.029, 0.993, 24)
+	if !errors.Is(err, holtwinters.ErrSeasonLength) {
+		t.Errorf("expected error to match ErrSeasonLength for white noise, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import "math"
+
+// Smoother smooths a stream of observations one at a time, in constant time and memory per observation, producing the
+// forecast for the next observation as each one is taken in.
+// Until the model it wraps has been fitted, a Smoother holds the first two seasons of observations and then fits the
+// model to them, after which each observation is smoothed using the same equations as PredictAdditive and
+// PredictMultiplicative, without keeping any history.
+type Smoother struct {
+	model  *Model
+	warmup []float64
+	// previous holds the seasonal component of each season updated by the last observation from before it was
+	// updated, to restore if the update is not finite
+	previous []float64
+}
+
+// NewSmoother creates a new Smoother that smooths observations using the model provided. If the model has already been
+// fitted observations are smoothed from its current state, otherwise the first two seasons of observations are used to
+// fit it.
+// model - The model to use for smoothing, its parameters must be valid
+func NewSmoother(model *Model) (*Smoother, error) {
+	err := model.validateParams()
+	if err != nil {
+		return nil, err
+	}
+	smoother := &Smoother{
+		model:    model,
+		previous: make([]float64, len(model.AdditionalSeasonLengths)+1),
+	}
+	if model.Observations == 0 {
+		smoother.warmup = make([]float64, 0, 2*model.longestSeasonLength())
+	}
+	return smoother, nil
+}
+
+// Push takes in the next observation in the stream, smoothing it and returning the forecast for the next observation.
+// If the Smoother is still collecting the observations to fit the model to, ready is false and the forecast is 0. If
+// the model can't be fitted to the observations collected with a finite state, such as a multiplicative model of a
+// season averaging 0, the oldest season is dropped and the Smoother keeps collecting until it can be. Non-finite
+// observations, and observations that would make the model's state non-finite, are treated as missing, whatever the
+// model's NonFinite policy, as Push can't report errors.
+// observation - The next observation
+func (s *Smoother) Push(observation float64) (forecast float64, ready bool) {
+	if s.model.Observations == 0 {
+		s.warmup = append(s.warmup, observation)
+		if len(s.warmup) < cap(s.warmup) || !s.fitWarmup() {
+			return 0, false
+		}
+		return s.model.oneStepForecast(), true
+	}
+	s.update(observation)
+	return s.model.oneStepForecast(), true
+}
+
+// fitWarmup fits the model to the observations collected, returning true if the model's state and forecasts for the
+// next season are finite. Otherwise the model is left unfitted and the oldest season of observations is dropped, so
+// that the observations kept still start at the model's phase
+func (s *Smoother) fitWarmup() bool {
+	unfitted := s.model.copy()
+	err := validateFiniteObservations(s.warmup)
+	if err == nil {
+		err = s.model.validateFiniteState(s.model.fit(s.warmup))
+	}
+	if err != nil {
+		*s.model = unfitted
+		seasonLength := s.model.longestSeasonLength()
+		copy(s.warmup, s.warmup[seasonLength:])
+		s.warmup = s.warmup[:len(s.warmup)-seasonLength]
+		return false
+	}
+	s.warmup = nil
+	return true
+}
+
+// update smooths the observation, treating it as missing if it would make the model's state or its forecast for the
+// next observation non-finite. Only the level, trend and the seasonal components for the observation's position in each
+// season are changed by an update, so only they are kept to restore, rather than a copy of the model
+func (s *Smoother) update(observation float64) {
+	m := s.model
+	observations, level, trend, sse, residuals := m.Observations, m.Level, m.Trend, m.sse, m.residuals
+	for k, seasonals := range m.Seasonals {
+		s.previous[k] = seasonals[m.seasonPosition(k, observations)]
+	}
+	result := m.update(observation)
+	finite := !missing(result) && !missing(m.Level) && !missing(m.Trend) && !missing(m.oneStepForecast())
+	for k, seasonals := range m.Seasonals {
+		finite = finite && !missing(seasonals[m.seasonPosition(k, observations)])
+	}
+	if finite {
+		return
+	}
+	m.Observations, m.Level, m.Trend, m.sse, m.residuals = observations, level, trend, sse, residuals
+	for k, seasonals := range m.Seasonals {
+		seasonals[m.seasonPosition(k, observations)] = s.previous[k]
+	}
+	m.update(math.NaN())
+}
+
+// Ready returns true if the Smoother has fitted its model and is producing forecasts
+func (s *Smoother) Ready() bool {
+	return s.model.Observations > 0
+}
+
+// Model returns the model the Smoother is using, which holds its current state. The model should not be changed while
+// the Smoother is in use.
+func (s *Smoother) Model() *Model {
+	return s.model
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestNewSmoother(t *testing.T) {
+	var tests = []struct {
+		description string
+		expectedErr error
+		model       *holtwinters.Model
+	}{
+		{
+			"Fail, season length too short",
+			&holtwinters.ParamError{Op: "prediction", Name: "season length", Value: 1, Range: "at least 2", Err: holtwinters.ErrSeasonLength},
+			holtwinters.NewModel(holtwinters.Additive, 1, 0.5, 0.3, 0.4),
+		},
+		{
+			"Fail, gamma too high",
+			&holtwinters.ParamError{Op: "prediction", Name: "gamma", Value: 1.4, Range: "between 0 and 1", Err: holtwinters.ErrGamma},
+			holtwinters.NewModel(holtwinters.Additive, 5, 0.5, 0.3, 1.4),
+		},
+		{
+			"Fail, unknown method",
+			&holtwinters.ParamError{Op: "prediction", Name: "method", Value: 2, Range: "additive or multiplicative", Err: holtwinters.ErrMethod},
+			holtwinters.NewModel(holtwinters.Method(2), 5, 0.5, 0.3, 0.4),
+		},
+		{
+			"Success",
+			nil,
+			holtwinters.NewModel(holtwinters.Additive, 5, 0.5, 0.3, 0.4),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := holtwinters.NewSmoother(test.model)
+			if !cmp.Equal(test.expectedErr, err, equateErrors) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+			}
+		})
+	}
+}
+
+func TestSmootherPush(t *testing.T) {
+	for _, method := range []holtwinters.Method{holtwinters.Additive, holtwinters.Multiplicative} {
+		t.Run(method.String(), func(t *testing.T) {
+			smoother, err := holtwinters.NewSmoother(holtwinters.NewModel(method, 12, 0.716, 0.029, 0.993))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The batch equivalent, fitted to the first two seasons and then updated with the rest
+			model := holtwinters.NewModel(method, 12, 0.716, 0.029, 0.993)
+
+			for i, val := range modelTestSeries {
+				forecast, ready := smoother.Push(val)
+				if i < 23 {
+					if ready || smoother.Ready() {
+						t.Fatalf("observation %d, smoother ready before two full seasons", i)
+					}
+					continue
+				}
+				if !ready || !smoother.Ready() {
+					t.Fatalf("observation %d, smoother not ready after two full seasons", i)
+				}
+
+				if i == 23 {
+					_, err = model.Fit(modelTestSeries[:24])
+				} else {
+					_, err = model.Update(val)
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				expected, err := model.Forecast(1)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if forecast != expected[0] {
+					t.Errorf("observation %d, forecast mismatch, want %v, got %v", i, expected[0], forecast)
+				}
+			}
+
+			if smoother.Model().Observations != len(modelTestSeries) {
+				t.Errorf("observations mismatch, want %d, got %d", len(modelTestSeries), smoother.Model().Observations)
+			}
+		})
+	}
+}
+
+func TestSmootherPushFittedModel(t *testing.T) {
+	model := holtwinters.NewModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993)
+	_, err := model.Fit(modelTestSeries[:36])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	smoother, err := holtwinters.NewSmoother(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !smoother.Ready() {
+		t.Fatalf("smoother with fitted model not ready")
+	}
+
+	// AllocsPerRun makes an extra warm up run
+	allocs := testing.AllocsPerRun(len(modelTestSeries)-37, func() {
+		smoother.Push(modelTestSeries[smoother.Model().Observations])
+	})
+	if allocs != 0 {
+		t.Errorf("push allocated memory, %f allocations per push", allocs)
+	}
+}
+
+func TestSmootherPushWarmupNotFinite(t *testing.T) {
+	smoother, err := holtwinters.NewSmoother(holtwinters.NewModel(holtwinters.Multiplicative, 12, 0.716, 0.029, 0.993))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two missing seasons have no finite observations to fit to, and seasons averaging 0 give NaN multiplicative
+	// seasonal components, so neither can be fitted to and the smoother should keep collecting
+	series := make([]float64, 0, 48+len(modelTestSeries))
+	for i := 0; i < 24; i++ {
+		series = append(series, math.NaN())
+	}
+	for i := 0; i < 24; i++ {
+		series = append(series, 0)
+	}
+	series = append(series, modelTestSeries...)
+
+	// The first two seasons of the series that can be fitted to start once every season averaging 0 has been dropped
+	model := holtwinters.NewModel(holtwinters.Multiplicative, 12, 0.716, 0.029, 0.993)
+	_, err = model.Fit(modelTestSeries[:24])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := model.Forecast(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, val := range series[:72] {
+		forecast, ready := smoother.Push(val)
+		if i < 71 {
+			if ready || smoother.Ready() {
+				t.Fatalf("observation %d, smoother ready without a finite state", i)
+			}
+			continue
+		}
+		if !ready || !smoother.Ready() {
+			t.Fatalf("observation %d, smoother not ready after two full finite seasons", i)
+		}
+		if forecast != expected[0] {
+			t.Errorf("forecast mismatch, want %v, got %v", expected[0], forecast)
+		}
+	}
+}
+
+func TestSmootherPushUpdateNotFinite(t *testing.T) {
+	model := &holtwinters.Model{
+		Method:       holtwinters.Multiplicative,
+		SeasonLength: 2,
+		Alpha:        0.5,
+		Beta:         0.1,
+		Gamma:        0.1,
+		Phi:          1,
+		Level:        10,
+		Trend:        1,
+		Seasonals:    [][]float64{{0, 2}},
+		Observations: 2,
+	}
+	smoother, err := holtwinters.NewSmoother(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Dividing by the seasonal component of 0 gives an infinite level, so the observation should be treated as missing
+	forecast, ready := smoother.Push(5)
+	if !ready {
+		t.Fatalf("smoother with fitted model not ready")
+	}
+	if forecast != 24 {
+		t.Errorf("forecast mismatch, want %v, got %v", 24, forecast)
+	}
+	expected := &holtwinters.Model{
+		Method:       holtwinters.Multiplicative,
+		SeasonLength: 2,
+		Alpha:        0.5,
+		Beta:         0.1,
+		Gamma:        0.1,
+		Phi:          1,
+		Level:        11,
+		Trend:        1,
+		Seasonals:    [][]float64{{0, 2}},
+		Observations: 3,
+	}
+	if !cmp.Equal(expected, smoother.Model(), cmpopts.IgnoreUnexported(holtwinters.Model{})) {
+		t.Errorf("model state mismatch (-want +got):\n%s", cmp.Diff(expected, smoother.Model(), cmpopts.IgnoreUnexported(holtwinters.Model{})))
+	}
+}
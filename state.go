@@ -0,0 +1,154 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ModelStateVersion is the schema version of the JSON produced by Model.MarshalJSON, increased whenever the schema
+// changes in a way older versions of this package can't read
+const ModelStateVersion = 1
+
+// modelState is the JSON representation of a Model
+type modelState struct {
+	Version                 int         `json:"version"`
+	Method                  string      `json:"method"`
+	SeasonLength            int         `json:"seasonLength"`
+	Alpha                   float64     `json:"alpha"`
+	Beta                    float64     `json:"beta"`
+	Gamma                   float64     `json:"gamma"`
+	AdditionalSeasonLengths []int       `json:"additionalSeasonLengths,omitempty"`
+	AdditionalGammas        []float64   `json:"additionalGammas,omitempty"`
+	Phi                     float64     `json:"phi"`
+	Phase                   int         `json:"phase"`
+	NonFinite               string      `json:"nonFinite,omitempty"`
+	Level                   float64     `json:"level"`
+	Trend                   float64     `json:"trend"`
+	Seasonals               [][]float64 `json:"seasonals"`
+	Observations            int         `json:"observations"`
+	SSE                     float64     `json:"sse"`
+	Residuals               int         `json:"residuals"`
+}
+
+// MarshalJSON encodes the model's parameters and fitted state as JSON, tagged with ModelStateVersion, so the model
+// can be persisted and restored with UnmarshalJSON to continue forecasting and updating without fitting it again
+func (m Model) MarshalJSON() ([]byte, error) {
+	// The default policy is left out so that the JSON is the same as before the policy could be set
+	nonFinite := ""
+	if m.NonFinite != SkipNonFinite {
+		nonFinite = m.NonFinite.String()
+	}
+	return json.Marshal(modelState{
+		Version:                 ModelStateVersion,
+		Method:                  m.Method.String(),
+		SeasonLength:            m.SeasonLength,
+		Alpha:                   m.Alpha,
+		Beta:                    m.Beta,
+		Gamma:                   m.Gamma,
+		AdditionalSeasonLengths: m.AdditionalSeasonLengths,
+		AdditionalGammas:        m.AdditionalGammas,
+		Phi:                     m.Phi,
+		Phase:                   m.Phase,
+		NonFinite:               nonFinite,
+		Level:                   m.Level,
+		Trend:                   m.Trend,
+		Seasonals:               m.Seasonals,
+		Observations:            m.Observations,
+		SSE:                     m.sse,
+		Residuals:               m.residuals,
+	})
+}
+
+// UnmarshalJSON restores a model from JSON produced by MarshalJSON. The schema version must be one this package can
+// read, and the parameters and fitted state of a fitted model are validated, the model is left unchanged if they are
+// invalid
+func (m *Model) UnmarshalJSON(data []byte) error {
+	state := modelState{}
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return err
+	}
+	if state.Version != ModelStateVersion {
+		return fmt.Errorf("Invalid model state; unsupported version %d, must be %d", state.Version, ModelStateVersion)
+	}
+
+	var method Method
+	switch state.Method {
+	case Additive.String():
+		method = Additive
+	case Multiplicative.String():
+		method = Multiplicative
+	default:
+		return fmt.Errorf("Invalid model state; method must be additive or multiplicative, is %q", state.Method)
+	}
+
+	var nonFinite NonFinitePolicy
+	switch state.NonFinite {
+	case "", SkipNonFinite.String():
+		nonFinite = SkipNonFinite
+	case RejectNonFinite.String():
+		nonFinite = RejectNonFinite
+	case ImputeNonFinite.String():
+		nonFinite = ImputeNonFinite
+	default:
+		return fmt.Errorf("Invalid model state; non-finite policy must be skip, reject or impute, is %q", state.NonFinite)
+	}
+
+	restored := Model{
+		Method:                  method,
+		SeasonLength:            state.SeasonLength,
+		Alpha:                   state.Alpha,
+		Beta:                    state.Beta,
+		Gamma:                   state.Gamma,
+		AdditionalSeasonLengths: state.AdditionalSeasonLengths,
+		AdditionalGammas:        state.AdditionalGammas,
+		Phi:                     state.Phi,
+		Phase:                   state.Phase,
+		NonFinite:               nonFinite,
+		Level:                   state.Level,
+		Trend:                   state.Trend,
+		Seasonals:               state.Seasonals,
+		Observations:            state.Observations,
+		sse:                     state.SSE,
+		residuals:               state.Residuals,
+	}
+	if restored.Observations < 0 {
+		return fmt.Errorf("Invalid model state; observations must be at least 0, is %d", restored.Observations)
+	}
+	if restored.Observations > 0 {
+		// Only a fitted model has state to check, an unfitted model's parameters are validated when it is fitted
+		err = restored.validateParams()
+		if err != nil {
+			return err
+		}
+		if len(restored.Seasonals) != len(restored.AdditionalSeasonLengths)+1 {
+			return fmt.Errorf("Invalid model state; must have seasonal components for each season length, season lengths: %d, seasonal component vectors: %d", len(restored.AdditionalSeasonLengths)+1, len(restored.Seasonals))
+		}
+		for k, seasonals := range restored.Seasonals {
+			if len(seasonals) != restored.seasonLength(k) {
+				return fmt.Errorf("Invalid model state; must have a seasonal component for each position in the season, season length: %d, seasonal components: %d", restored.seasonLength(k), len(seasonals))
+			}
+		}
+		if restored.residuals < 0 || restored.residuals >= restored.Observations {
+			return fmt.Errorf("Invalid model state; residuals must be at least 0 and less than the observations %d, is %d", restored.Observations, restored.residuals)
+		}
+	}
+	*m = restored
+	return nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2019 Jamie Thompson.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package holtwinters_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/holtwinters"
+)
+
+func TestModelMarshalJSON(t *testing.T) {
+	model := holtwinters.NewDampedModel(holtwinters.Multiplicative, 2, 0.5, 0.25, 0.125, 0.75)
+	model.Phase = 1
+	_, err := model.Fit([]float64{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model.Level = 4
+	model.Trend = 0.5
+	model.Seasonals = [][]float64{{0.75, 1.25}}
+
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"version":1,"method":"multiplicative","seasonLength":2,"alpha":0.5,"beta":0.25,"gamma":0.125,"phi":0.75,` +
+		`"phase":1,"level":4,"trend":0.5,"seasonals":[[0.75,1.25]],"observations":5,"sse":5.1365567478961305,"residuals":4}`
+	if !cmp.Equal(expected, string(encoded)) {
+		t.Errorf("JSON mismatch (-want +got):\n%s", cmp.Diff(expected, string(encoded)))
+	}
+}
+
+func TestModelJSONRoundTrip(t *testing.T) {
+	model := holtwinters.NewDampedModel(holtwinters.Additive, 12, 0.716, 0.029, 0.993, 0.9)
+	model.Phase = 3
+	model.NonFinite = holtwinters.RejectNonFinite
+	_, err := model.Fit(modelTestSeries[:60])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encoded, err := json.Marshal(model)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored := &holtwinters.Model{}
+	err = json.Unmarshal(encoded, restored)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.NonFinite != model.NonFinite {
+		t.Errorf("non-finite policy mismatch, want %s, got %s", model.NonFinite, restored.NonFinite)
+	}
+
+	for _, val := range modelTestSeries[60:] {
+		expected, err := model.Update(val)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		smoothed, err := restored.Update(val)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected != smoothed {
+			t.Errorf("smoothed value mismatch, want %v, got %v", expected, smoothed)
+		}
+	}
+
+	expected, err := model.ForecastIntervals(24, 0.95)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	intervals, err := restored.ForecastIntervals(24, 0.95)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(expected, intervals) {
+		t.Errorf("intervals mismatch (-want +got):\n%s", cmp.Diff(expected, intervals))
+	}
+}
+
+func TestModelUnmarshalJSON(t *testing.T) {
+
+	var tests = []struct {
+		description string
+		expected    *holtwinters.Model
+		expectedErr error
+		data        string
+	}{
+		{
+			"Fail, invalid JSON",
+			&holtwinters.Model{},
+			errors.New("unexpected end of JSON input"),
+			`{"version": 1`,
+		},
+		{
+			"Fail, unsupported version",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; unsupported version 2, must be 1"),
+			`{"version": 2, "method": "additive"}`,
+		},
+		{
+			"Fail, missing version",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; unsupported version 0, must be 1"),
+			`{"method": "additive"}`,
+		},
+		{
+			"Fail, unknown method",
+			&holtwinters.Model{},
+			errors.New(`Invalid model state; method must be additive or multiplicative, is "exponential"`),
+			`{"version": 1, "method": "exponential"}`,
+		},
+		{
+			"Fail, negative observations",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; observations must be at least 0, is -1"),
+			`{"version": 1, "method": "additive", "observations": -1}`,
+		},
+		{
+			"Fail, invalid alpha",
+			&holtwinters.Model{},
+			&holtwinters.ParamError{Op: "prediction", Name: "alpha", Value: 2.000000, Range: "between 0 and 1", Err: holtwinters.ErrAlpha},
+			`{"version": 1, "method": "additive", "seasonLength": 2, "alpha": 2, "phi": 1, "seasonals": [[1, 2]], "observations": 3}`,
+		},
+		{
+			"Fail, wrong number of seasonal components",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; must have a seasonal component for each position in the season, season length: 3, seasonal components: 2"),
+			`{"version": 1, "method": "additive", "seasonLength": 3, "phi": 1, "seasonals": [[1, 2]], "observations": 3}`,
+		},
+		{
+			"Fail, wrong number of seasonal component vectors",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; must have seasonal components for each season length, season lengths: 2, seasonal component vectors: 1"),
+			`{"version": 1, "method": "additive", "seasonLength": 2, "additionalSeasonLengths": [3], "additionalGammas": [0.1], "phi": 1, "seasonals": [[1, 2]], "observations": 3}`,
+		},
+		{
+			"Fail, too many residuals",
+			&holtwinters.Model{},
+			errors.New("Invalid model state; residuals must be at least 0 and less than the observations 3, is 3"),
+			`{"version": 1, "method": "additive", "seasonLength": 2, "phi": 1, "seasonals": [[1, 2]], "observations": 3, "residuals": 3}`,
+		},
+		{
+			"Fail, unknown non-finite policy",
+			&holtwinters.Model{},
+			errors.New(`Invalid model state; non-finite policy must be skip, reject or impute, is "ignore"`),
+			`{"version": 1, "method": "additive", "nonFinite": "ignore"}`,
+		},
+		{
+			"Success, impute non-finite",
+			&holtwinters.Model{
+				Method:       holtwinters.Additive,
+				SeasonLength: 2,
+				Alpha:        0.5,
+				Phi:          1,
+				NonFinite:    holtwinters.ImputeNonFinite,
+			},
+			nil,
+			`{"version": 1, "method": "additive", "seasonLength": 2, "alpha": 0.5, "phi": 1, "nonFinite": "impute"}`,
+		},
+		{
+			"Success, unfitted",
+			holtwinters.NewModel(holtwinters.Multiplicative, holtwinters.AutoSeasonLength, 0.5, 0.1, 0.1),
+			nil,
+			`{"version": 1, "method": "multiplicative", "seasonLength": -1, "alpha": 0.5, "beta": 0.1, "gamma": 0.1, "phi": 1}`,
+		},
+		{
+			"Success, fitted",
+			&holtwinters.Model{
+				Method:       holtwinters.Additive,
+				SeasonLength: 2,
+				Alpha:        0.5,
+				Beta:         0.1,
+				Gamma:        0.1,
+				Phi:          0.9,
+				Phase:        1,
+				Level:        10,
+				Trend:        1,
+				Seasonals:    [][]float64{{-1, 1}},
+				Observations: 6,
+			},
+			nil,
+			`{"version": 1, "method": "additive", "seasonLength": 2, "alpha": 0.5, "beta": 0.1, "gamma": 0.1, "phi": 0.9, "phase": 1, "level": 10, "trend": 1, "seasonals": [[-1, 1]], "observations": 6}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			model := &holtwinters.Model{}
+			err := json.Unmarshal([]byte(test.data), model)
+
+			if !cmp.Equal(&err, &test.expectedErr, equateErrors) {
+				t.Errorf("Error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrors))
+				return
+			}
+
+			if !cmp.Equal(test.expected, model, cmp.AllowUnexported(holtwinters.Model{})) {
+				t.Errorf("model mismatch (-want +got):\n%s", cmp.Diff(test.expected, model, cmp.AllowUnexported(holtwinters.Model{})))
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+#!/usr/bin/env python3
+# Copyright 2019 Jamie Thompson.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# 	http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+"""Generates the reference fixtures used by the reference tests in holtwinters_test.go and interval_test.go using the
+Holt-Winters implementation of statsmodels, statsmodels.tsa.holtwinters.ExponentialSmoothing.
+
+statsmodels estimates its own initial state, so each model is given the package's initial state using
+initialization_method="known", calculated in the same way as the package as described in the README. The package takes
+the first observation as its initial state, so statsmodels is given the rest of the series, with the seasonal components
+starting from the second observation's position in the season. Each smoothed value is then the level and trend after
+the observation, combined with the seasonal component after the observation, and the predictions are statsmodels'
+forecasts.
+
+The package's additive seasonal update is gamma * (y - level), where level is the updated level, this is the same as
+statsmodels' gamma * (y - previous level - trend) with gamma scaled by 1 - alpha, so statsmodels is given that gamma.
+
+statsmodels does not support missing values, so the tests with missing values are not generated by this script.
+
+Usage:
+
+    pip install statsmodels
+    python3 testdata/statsmodels_reference.py
+
+Prints the expected values for each test case, which are compared with the package's results allowing for floating
+point differences of 1e-9.
+"""
+
+import math
+from statistics import NormalDist
+
+import numpy as np
+from statsmodels.tsa.holtwinters import ExponentialSmoothing
+
+
+def initial_trend(series, season_length):
+    """The average change between the first two seasons, or between the first two observations if there are not two
+    seasons to compare"""
+    if len(series) >= season_length * 2:
+        return sum((series[i + season_length] - series[i]) / season_length for i in range(season_length)) / season_length
+    return series[1] - series[0]
+
+
+def initial_seasonals(series, season_length, multiplicative):
+    """The average of each position in the season relative to its season's average, over every full season"""
+    n_seasons = len(series) // season_length
+    averages = [sum(series[j * season_length:(j + 1) * season_length]) / season_length for j in range(n_seasons)]
+    seasonals = []
+    for i in range(season_length):
+        relative = [series[season_length * j + i] / averages[j] if multiplicative else series[season_length * j + i] - averages[j]
+                    for j in range(n_seasons)]
+        seasonals.append(sum(relative) / n_seasons)
+    return seasonals
+
+
+def holt_winters(series, season_length, alpha, beta, gamma, phi, prediction_length, multiplicative):
+    """Smooths the series and forecasts the prediction length following it using statsmodels, returning the smoothed
+    series with the forecasts appended, and the statsmodels results"""
+    seasonals = initial_seasonals(series, season_length, multiplicative)
+    damped = phi != 1
+    model = ExponentialSmoothing(
+        np.asarray(series[1:], dtype=float),
+        trend="add",
+        damped_trend=damped,
+        seasonal="mul" if multiplicative else "add",
+        seasonal_periods=season_length,
+        initialization_method="known",
+        initial_level=series[0],
+        initial_trend=initial_trend(series, season_length),
+        # Seasonal components from the position of the second observation, the first given to statsmodels
+        initial_seasonal=seasonals[1:] + seasonals[:1],
+    )
+    fit = model.fit(
+        smoothing_level=alpha,
+        smoothing_trend=beta,
+        smoothing_seasonal=gamma if multiplicative else gamma * (1 - alpha),
+        damping_trend=phi if damped else None,
+        optimized=False,
+    )
+    level, trend, season = np.asarray(fit.level), np.asarray(fit.trend), np.asarray(fit.season)
+    if multiplicative:
+        smoothed = (level + phi * trend) * season
+    else:
+        smoothed = level + phi * trend + season
+    result = [float(series[0])] + [float(val) for val in smoothed]
+    if prediction_length > 0:
+        result += [float(val) for val in np.asarray(fit.forecast(prediction_length))]
+    return result, fit
+
+
+def additive_intervals(series, season_length, alpha, beta, gamma, prediction_length, confidence):
+    """Forecasts with prediction intervals for the additive method, as (forecast, lower, upper) for each step, using the
+    class 1 forecast variance of Hyndman et al., Forecasting with Exponential Smoothing, 6.3, with the mean squared
+    one-step-ahead error of statsmodels' fit as the residual variance"""
+    result, fit = holt_winters(series, season_length, alpha, beta, gamma, 1, prediction_length, False)
+    forecasts = result[len(series):]
+    residual_variance = fit.sse / (len(series) - 1)
+    z = NormalDist().inv_cdf((1 + confidence) / 2)
+    intervals = []
+    for h, forecast in enumerate(forecasts, start=1):
+        coefficients = [alpha * (1 + j * beta) + (gamma * (1 - alpha) if j % season_length == 0 else 0)
+                        for j in range(1, h)]
+        half_width = z * math.sqrt(residual_variance * (1 + sum(c * c for c in coefficients)))
+        intervals.append((forecast, forecast - half_width, forecast + half_width))
+    return intervals
+
+
+short = [1, 2, 3, 2, 1]
+two_seasons = [1, 2, 3, 2, 1, 1.1, 1.9, 3.1, 2.1, 1.1]
+airline = [30, 21, 29, 31, 40, 48, 53, 47, 37, 39, 31, 29, 17, 9, 20, 24, 27, 35, 41, 38,
+           27, 31, 27, 26, 21, 13, 21, 18, 33, 35, 40, 36, 22, 24, 21, 20, 17, 14, 17, 19,
+           26, 29, 40, 31, 20, 24, 18, 26, 17, 9, 17, 21, 28, 32, 46, 33, 23, 28, 22, 27,
+           18, 8, 17, 21, 31, 34, 44, 38, 31, 30, 26, 32]
+amplitude = [100, 220, 480, 210, 95, 130, 270, 610, 260, 120, 160, 330, 740, 320, 150]
+
+# Test, case, series, season length, alpha, beta, gamma, phi, prediction length
+smoothing_cases = [
+    ("TestPredictMultiplicative", "Success, 1 season, no prediction", short, 5, 0.9, 0.9, 0.9, 1, 0),
+    ("TestPredictMultiplicative", "Success, 1 and a half seasons data", [1, 2, 3, 2, 1, 1.1, 1.9, 3.1], 5, 0.9, 0.9, 0.9, 1, 5),
+    ("TestPredictMultiplicative", "Success, less than 2 seasons data", short, 5, 0.9, 0.9, 0.9, 1, 5),
+    ("TestPredictMultiplicative", "Success, 2 seasons data", two_seasons, 5, 0.9, 0.9, 0.9, 1, 5),
+    ("TestPredictMultiplicative", "Success, more than 2 seasons data", airline, 12, 0.716, 0.029, 0.993, 1, 24),
+    ("TestPredictMultiplicative", "Success, large seasonal amplitude with trend", amplitude, 5, 0.5, 0.3, 0.4, 1, 10),
+    ("TestPredictAdditiveDamped", "Success, 2 seasons data", two_seasons, 5, 0.9, 0.9, 0.9, 0.8, 5),
+    ("TestPredictAdditiveDamped", "Success, 3 seasons data with trend, long horizon", amplitude, 5, 0.5, 0.3, 0.4, 0.9, 15),
+    ("TestPredictMultiplicativeDamped", "Success, 2 seasons data", two_seasons, 5, 0.9, 0.9, 0.9, 0.8, 5),
+    ("TestPredictMultiplicativeDamped", "Success, 3 seasons data with trend, long horizon", amplitude, 5, 0.5, 0.3, 0.4, 0.9, 15),
+]
+
+# Test, case, series, season length, alpha, beta, gamma, prediction length, confidence
+interval_cases = [
+    ("TestPredictAdditiveIntervals", "Success, 95% intervals", two_seasons, 5, 0.5, 0.3, 0.4, 7, 0.95),
+]
+
+
+def main():
+    for test, case, series, season_length, alpha, beta, gamma, phi, prediction_length in smoothing_cases:
+        multiplicative = "Multiplicative" in test
+        result, _ = holt_winters(series, season_length, alpha, beta, gamma, phi, prediction_length, multiplicative)
+        print(f"{test}/{case}")
+        print("[]float64{" + ", ".join(repr(val) for val in result) + "}")
+    for test, case, series, season_length, alpha, beta, gamma, prediction_length, confidence in interval_cases:
+        print(f"{test}/{case}")
+        for interval in additive_intervals(series, season_length, alpha, beta, gamma, prediction_length, confidence):
+            print("{" + ", ".join(repr(val) for val in interval) + "},")
+
+
+if __name__ == "__main__":
+    main()